@@ -29,7 +29,8 @@ func (c clauses) Args() (args []interface{}) {
 }
 
 type fromClause struct {
-	From string
+	From      string
+	Arguments []interface{}
 }
 
 type fromClauses []fromClause
@@ -45,3 +46,10 @@ func (c fromClauses) String() string {
 	}
 	return strings.Join(cs, ", ")
 }
+
+func (c fromClauses) Args() (args []interface{}) {
+	for _, clause := range c {
+		args = append(args, clause.Arguments...)
+	}
+	return
+}
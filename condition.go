@@ -0,0 +1,231 @@
+package goala
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Condition is a typed filter that can be passed to `WhereCond`. Conditions
+// are compiled into where clauses with the column names quoted for the
+// dialect of the connection.
+//
+//	c.WhereCond(Or(Eq{"name": "mark"}, In("id", ids)), Not(IsNull("email")))
+type Condition interface {
+	toClause(d dialect) clause
+}
+
+// WhereCond will append each condition as a where clause to the query.
+//
+//	c.WhereCond(Eq{"name": "mark"}, Like("email", "%@example.com"))
+func (c *Connection) WhereCond(conds ...Condition) *Query {
+	return Q(c).WhereCond(conds...)
+}
+
+// WhereCond will append each condition as a where clause to the query.
+//
+//	q.WhereCond(Eq{"name": "mark"}, Like("email", "%@example.com"))
+func (q *Query) WhereCond(conds ...Condition) *Query {
+	if q.RawSQL.Fragment != "" {
+		fmt.Println("Warning: Query is setup to use raw SQL")
+		return q
+	}
+	for _, cond := range conds {
+		cl := cond.toClause(q.Connection.Dialect)
+		stmt, args, _, err := expandSubqueries(cl.Fragment, cl.Arguments)
+		if err != nil {
+			q.addError(errors.Wrap(err, "where"))
+			return q
+		}
+		q.whereClauses = append(q.whereClauses, clause{stmt, args})
+	}
+	return q
+}
+
+// Eq matches columns against values. A nil value matches NULL, a slice
+// matches any of its elements and a `*Query` is compiled as a subquery.
+// Several columns are joined with AND.
+//
+//	Eq{"name": "mark", "deleted_at": nil}
+type Eq map[string]interface{}
+
+func (e Eq) toClause(d dialect) clause {
+	keys := make([]string, 0, len(e))
+	for k := range e {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	conds := make([]Condition, len(keys))
+	for i, k := range keys {
+		conds[i] = eqCondition(k, e[k])
+	}
+	if len(conds) == 1 {
+		return conds[0].toClause(d)
+	}
+	return And(conds...).toClause(d)
+}
+
+func eqCondition(column string, value interface{}) Condition {
+	if value == nil {
+		return IsNull(column)
+	}
+	if _, ok := value.(*Query); ok {
+		return In(column, value)
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		return In(column, value)
+	}
+	return compare(column, "=", value)
+}
+
+type comparison struct {
+	column string
+	op     string
+	value  interface{}
+}
+
+func compare(column, op string, value interface{}) Condition {
+	return comparison{column, op, value}
+}
+
+func (c comparison) toClause(d dialect) clause {
+	return clause{fmt.Sprintf("%s %s ?", d.Quote(c.column), c.op), []interface{}{c.value}}
+}
+
+// Like matches a column against a pattern.
+//
+//	Like("email", "%@example.com")
+func Like(column, pattern string) Condition {
+	return compare(column, "LIKE", pattern)
+}
+
+type inCondition struct {
+	column string
+	values interface{}
+}
+
+// In matches a column against a slice of values or a `*Query`, which is
+// compiled as a subquery. An empty slice matches nothing.
+//
+//	In("id", []int{1, 2, 3})
+//	In("user_id", c.Select("id").From(&User{}))
+func In(column string, values interface{}) Condition {
+	return inCondition{column, values}
+}
+
+func (c inCondition) toClause(d dialect) clause {
+	column := d.Quote(c.column)
+	if sub, ok := c.values.(*Query); ok {
+		return clause{fmt.Sprintf("%s IN (?)", column), []interface{}{sub}}
+	}
+	v := reflect.ValueOf(c.values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return clause{fmt.Sprintf("%s IN (?)", column), []interface{}{c.values}}
+	}
+	if v.Len() == 0 {
+		return clause{"1=0", []interface{}{}}
+	}
+	placeholders := make([]string, v.Len())
+	args := make([]interface{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		placeholders[i] = "?"
+		args[i] = v.Index(i).Interface()
+	}
+	return clause{fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ",")), args}
+}
+
+type betweenCondition struct {
+	column string
+	lo, hi interface{}
+}
+
+// Between matches a column against an inclusive range.
+//
+//	Between("created_at", start, end)
+func Between(column string, lo, hi interface{}) Condition {
+	return betweenCondition{column, lo, hi}
+}
+
+func (c betweenCondition) toClause(d dialect) clause {
+	return clause{fmt.Sprintf("%s BETWEEN ? AND ?", d.Quote(c.column)), []interface{}{c.lo, c.hi}}
+}
+
+type nullCondition struct {
+	column string
+}
+
+// IsNull matches rows where the column is NULL. Wrap it in `Not` to match
+// rows where it is set.
+func IsNull(column string) Condition {
+	return nullCondition{column}
+}
+
+func (c nullCondition) toClause(d dialect) clause {
+	return clause{fmt.Sprintf("%s IS NULL", d.Quote(c.column)), []interface{}{}}
+}
+
+type exprCondition struct {
+	stmt string
+	args []interface{}
+}
+
+// Expr wraps a raw where fragment so that it can be combined with other
+// conditions. Arguments follow the same rules as `Where`.
+//
+//	Or(Expr("age > ?", 21), IsNull("age"))
+func Expr(stmt string, args ...interface{}) Condition {
+	return exprCondition{stmt, args}
+}
+
+func (c exprCondition) toClause(d dialect) clause {
+	return clause{c.stmt, c.args}
+}
+
+type notCondition struct {
+	cond Condition
+}
+
+// Not negates a condition.
+func Not(cond Condition) Condition {
+	return notCondition{cond}
+}
+
+func (c notCondition) toClause(d dialect) clause {
+	cl := c.cond.toClause(d)
+	return clause{fmt.Sprintf("NOT (%s)", cl.Fragment), cl.Arguments}
+}
+
+type groupCondition struct {
+	sep   string
+	conds []Condition
+}
+
+// And matches rows that satisfy all of the conditions.
+func And(conds ...Condition) Condition {
+	return groupCondition{" AND ", conds}
+}
+
+// Or matches rows that satisfy any of the conditions.
+func Or(conds ...Condition) Condition {
+	return groupCondition{" OR ", conds}
+}
+
+func (c groupCondition) toClause(d dialect) clause {
+	if len(c.conds) == 0 {
+		if c.sep == " OR " {
+			return clause{"1=0", []interface{}{}}
+		}
+		return clause{"1=1", []interface{}{}}
+	}
+	cls := make(clauses, len(c.conds))
+	for i, cond := range c.conds {
+		cls[i] = cond.toClause(d)
+	}
+	if len(cls) == 1 {
+		return cls[0]
+	}
+	return clause{fmt.Sprintf("(%s)", cls.Join(c.sep)), cls.Args()}
+}
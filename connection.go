@@ -3,6 +3,7 @@ package goala
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
@@ -16,9 +17,27 @@ const (
 )
 
 type Connection struct {
-	ID      uuid.UUID
-	DB      *sqlx.DB
-	Dialect dialect
+	ID          uuid.UUID
+	DB          *sqlx.DB
+	Dialect     dialect
+	Clock       Clock
+	IDGenerator IDGenerator
+
+	namingStrategy NamingStrategy
+}
+
+// Clock provides the current time used for timestamps. Set `Connection.Clock`
+// to freeze time in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// now returns the current time in UTC from the clock of the connection
+func (c *Connection) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock.Now().UTC()
+	}
+	return time.Now().UTC()
 }
 
 func (c *Connection) Close() {
@@ -0,0 +1,290 @@
+package goala
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/estenssoros/goala/nulls"
+	"github.com/estenssoros/goala/values"
+	"github.com/pkg/errors"
+)
+
+// csvChunkSize is the number of records ImportCSV inserts per statement
+const csvChunkSize = 500
+
+// CSVError is the error of a line of a CSV import
+type CSVError struct {
+	Line int
+	Err  error
+}
+
+func (e CSVError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// CSVErrors are the lines a CSV import skipped
+type CSVErrors []CSVError
+
+func (e CSVErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ExportCSV streams the rows of a query as CSV with a header of the column
+// names. models is a pointer to a slice of the model and only sets its type.
+// NULL values are written as empty fields. A nil query exports every row.
+//
+//	c.ExportCSV(os.Stdout, c.Where("active = ?", true), &[]User{})
+func (c *Connection) ExportCSV(w io.Writer, q *Query, models interface{}) error {
+	if models == nil {
+		return errors.New("export csv: models is nil")
+	}
+	if q == nil {
+		q = Q(c)
+	}
+	t := modelType(models)
+	it, err := q.Iter(reflect.New(t).Interface())
+	if err != nil {
+		return errors.Wrap(err, "export csv")
+	}
+	defer it.Close()
+	cols, err := it.Columns()
+	if err != nil {
+		return errors.Wrap(err, "export csv")
+	}
+	meta := c.newModel(reflect.New(t).Interface()).metadata()
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cols); err != nil {
+		return errors.Wrap(err, "export csv")
+	}
+	for it.Next() {
+		v := reflect.New(t)
+		if err := it.Scan(v.Interface()); err != nil {
+			return errors.Wrap(err, "export csv")
+		}
+		record, err := csvRecord(v.Elem(), meta, cols)
+		if err != nil {
+			return errors.Wrap(err, "export csv")
+		}
+		if err := cw.Write(record); err != nil {
+			return errors.Wrap(err, "export csv")
+		}
+	}
+	if err := it.Err(); err != nil {
+		return errors.Wrap(err, "export csv")
+	}
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "export csv")
+}
+
+// csvRecord formats the columns of a struct as CSV fields
+func csvRecord(v reflect.Value, meta *structMetadata, cols []string) ([]string, error) {
+	record := make([]string, len(cols))
+	for i, name := range cols {
+		col, ok := meta.column(name)
+		if !ok {
+			return nil, errors.Errorf("no field for column %s", name)
+		}
+		value, ok := fieldValue(v, col)
+		if !ok || (value.Kind() == reflect.Ptr && value.IsNil()) {
+			continue
+		}
+		dv, err := driverValue(reflect.Indirect(value))
+		if err != nil {
+			return nil, errors.Wrapf(err, "column %s", name)
+		}
+		if dv == nil {
+			continue
+		}
+		val, err := values.Of(dv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "column %s", name)
+		}
+		record[i] = val.String()
+	}
+	return record, nil
+}
+
+// ImportCSV parses CSV records with a header of column names into the model
+// and inserts them in chunks. model is a pointer to a struct and only sets
+// its type. Empty fields keep the zero value, so they are NULL for pointers
+// and the nulls types and empty ids are generated. Lines that cannot be
+// parsed or inserted are skipped and returned as CSVErrors along with the
+// number of records inserted. The before create hooks run once per record
+// as it is parsed. When a chunk fails to insert its records are retried one
+// by one, so only the failing lines are skipped.
+//
+//	n, err := c.ImportCSV(f, &User{})
+func (c *Connection) ImportCSV(r io.Reader, model interface{}) (int, error) {
+	if model == nil {
+		return 0, errors.New("import csv: model is nil")
+	}
+	t := modelType(model)
+	meta := c.newModel(reflect.New(t).Interface()).metadata()
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return 0, errors.Wrap(err, "import csv: reading header")
+	}
+	fields := make([]columnField, len(header))
+	for i, name := range header {
+		col, ok := meta.column(strings.TrimSpace(name))
+		if !ok {
+			return 0, errors.Errorf("import csv: no field for column %s", name)
+		}
+		fields[i] = col
+	}
+	var (
+		inserted int
+		lineErrs CSVErrors
+	)
+	chunk := reflect.New(reflect.SliceOf(t))
+	// lines are the line numbers of the records in chunk
+	lines := []int{}
+	flush := func() {
+		if chunk.Elem().Len() == 0 {
+			return
+		}
+		retry := c.Dialect.CreateMany(c.db(), c.newModel(chunk.Interface())) != nil
+		for i, line := range lines {
+			m := c.newModel(chunk.Elem().Index(i).Addr().Interface())
+			if retry {
+				if err := c.Dialect.Create(c.db(), m); err != nil {
+					lineErrs = append(lineErrs, CSVError{line, err})
+					continue
+				}
+			}
+			inserted++
+			if err := m.afterCreateHooks(c); err != nil {
+				lineErrs = append(lineErrs, CSVError{line, err})
+			}
+		}
+		chunk = reflect.New(reflect.SliceOf(t))
+		lines = lines[:0]
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if pe, ok := err.(*csv.ParseError); ok {
+			lineErrs = append(lineErrs, CSVError{pe.Line, pe.Err})
+			continue
+		}
+		if err != nil {
+			return inserted, errors.Wrap(err, "import csv")
+		}
+		line, _ := cr.FieldPos(0)
+		v := reflect.New(t).Elem()
+		if err := parseCSVRecord(v, fields, record); err != nil {
+			lineErrs = append(lineErrs, CSVError{line, err})
+			continue
+		}
+		if err := c.prepareCSVRecord(v); err != nil {
+			lineErrs = append(lineErrs, CSVError{line, err})
+			continue
+		}
+		chunk.Elem().Set(reflect.Append(chunk.Elem(), v))
+		lines = append(lines, line)
+		if chunk.Elem().Len() >= csvChunkSize {
+			flush()
+		}
+	}
+	flush()
+	if len(lineErrs) > 0 {
+		return inserted, lineErrs
+	}
+	return inserted, nil
+}
+
+// prepareCSVRecord runs the before create hooks of a parsed record and sets
+// its id and timestamps
+func (c *Connection) prepareCSVRecord(v reflect.Value) error {
+	m := c.newModel(v.Addr().Interface())
+	if err := m.beforeCreateHooks(c); err != nil {
+		return err
+	}
+	return c.prepareCreate(m)
+}
+
+func parseCSVRecord(v reflect.Value, fields []columnField, record []string) error {
+	for i, col := range fields {
+		if err := setFieldText(fieldValueAlloc(v, col), record[i]); err != nil {
+			return errors.Wrapf(err, "column %s", col.Name)
+		}
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// setFieldText sets a struct field from its text in a CSV record. Empty
+// text leaves the zero value, which is NULL for pointers and the nulls
+// types.
+func setFieldText(fv reflect.Value, text string) error {
+	if text == "" {
+		return nil
+	}
+	if fv.Kind() == reflect.Ptr {
+		fv.Set(reflect.New(fv.Type().Elem()))
+		fv = fv.Elem()
+	}
+	switch {
+	case fv.Type() == timeType:
+		t, err := nulls.ParseTime(text)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	case fv.Addr().Type().Implements(scannerType):
+		return fv.Addr().Interface().(sql.Scanner).Scan(text)
+	case fv.Addr().Type().Implements(textUnmarshalerType):
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(text, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(text, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.Uint8 {
+			return errors.Errorf("unsupported field type %s", fv.Type())
+		}
+		fv.SetBytes([]byte(text))
+	default:
+		return errors.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
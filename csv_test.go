@@ -0,0 +1,140 @@
+package goala
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/estenssoros/goala/nulls"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+type csvStruct struct {
+	ID    uuid.UUID    `db:"id"`
+	Name  string       `db:"name"`
+	Age   int          `db:"age"`
+	Email nulls.String `db:"email"`
+	Score *float64     `db:"score"`
+}
+
+func (csvStruct) TableName() string { return "csv_struct" }
+
+func TestCSVRoundTrip(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&csvStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.Must(uuid.NewV4())
+	input := strings.Join([]string{
+		"id,name,age,email,score",
+		fmt.Sprintf("%s,mark,40,mark@example.com,1.5", id),
+		",\"o'brien, jr\",30,,",
+		",bad,forty,,",
+	}, "\n")
+	n, err := db.ImportCSV(strings.NewReader(input), &csvStruct{})
+	if want, have := 2, n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	lineErrs, ok := errors.Cause(err).(CSVErrors)
+	if !ok || len(lineErrs) != 1 || lineErrs[0].Line != 4 {
+		t.Fatalf("want an error on line 4, have: %v", err)
+	}
+	found := &csvStruct{}
+	if err := db.Where("name = ?", "o'brien, jr").First(found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Email.Valid || found.Score != nil || found.ID == uuid.Nil {
+		t.Errorf("want empty fields to be NULL, have: %+v", found)
+	}
+	buf := &bytes.Buffer{}
+	if err := db.ExportCSV(buf, Q(db).Order("age desc"), &[]csvStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"id,name,age,email,score",
+		fmt.Sprintf("%s,mark,40,mark@example.com,1.5", id),
+		fmt.Sprintf("%s,\"o'brien, jr\",30,,", found.ID),
+		"",
+	}, "\n")
+	if have := buf.String(); want != have {
+		t.Errorf("want: %q have: %q", want, have)
+	}
+}
+
+func TestImportCSVInsertErrors(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if _, err := db.DB.Exec("CREATE TABLE csv_struct (id TEXT, name TEXT, age INT CHECK (age >= 0), email TEXT, score REAL)"); err != nil {
+		t.Fatal(err)
+	}
+	input := strings.Join([]string{
+		"name,age",
+		"mark,40",
+		"negative,-1",
+		"jane,30",
+	}, "\n")
+	n, err := db.ImportCSV(strings.NewReader(input), &csvStruct{})
+	if want, have := 2, n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	lineErrs, ok := errors.Cause(err).(CSVErrors)
+	if !ok || len(lineErrs) != 1 || lineErrs[0].Line != 3 {
+		t.Fatalf("want an error on line 3, have: %v", err)
+	}
+	found := []csvStruct{}
+	if err := db.All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 2, len(found); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+type hookedCSVStruct struct {
+	ID   uuid.UUID `db:"id"`
+	Name string    `db:"name"`
+	Age  int       `db:"age"`
+}
+
+func (hookedCSVStruct) TableName() string { return "hooked_csv" }
+
+var hookedCSVCalls int
+
+func (h *hookedCSVStruct) BeforeCreate(c *Connection) error {
+	hookedCSVCalls++
+	return nil
+}
+
+func TestImportCSVHooksRunOnce(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if _, err := db.DB.Exec("CREATE TABLE hooked_csv (id TEXT, name TEXT, age INT CHECK (age >= 0))"); err != nil {
+		t.Fatal(err)
+	}
+	hookedCSVCalls = 0
+	input := "name,age\nmark,40\nnegative,-1\njane,30"
+	n, err := db.ImportCSV(strings.NewReader(input), &hookedCSVStruct{})
+	if want, have := 2, n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if _, ok := errors.Cause(err).(CSVErrors); !ok {
+		t.Errorf("want CSVErrors, have: %v", err)
+	}
+	if want, have := 3, hookedCSVCalls; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func TestCSVNilModels(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.ExportCSV(&bytes.Buffer{}, nil, nil); err == nil {
+		t.Error("expected error for nil models")
+	}
+	if _, err := db.ImportCSV(strings.NewReader("name\n"), nil); err == nil {
+		t.Error("expected error for nil model")
+	}
+}
@@ -1,10 +1,13 @@
 package goala
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
+	"github.com/estenssoros/goala/values"
 	interpol "github.com/imkira/go-interpol"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
@@ -16,21 +19,64 @@ var emptyUUID = uuid.Nil.String()
 type dialect interface {
 	Name() string
 	TranslateSQL(string) string
+	Quote(string) string
+	JSONExtract(string) string
 	Create(*sqlx.DB, *Model) error
 	CreateMany(*sqlx.DB, *Model) error
 	Update(*sqlx.DB, *Model) error
+	UpdateColumns(*sqlx.DB, *Model, []string) error
+	UpdateAll(*sqlx.DB, *Model, Query, map[string]interface{}) (int64, error)
 	Destroy(*sqlx.DB, *Model) error
 	DestroyMany(*sqlx.DB, *Model) error
+	DeleteWhere(*sqlx.DB, *Model, Query) (int64, error)
+	SoftDestroy(*sqlx.DB, *Model, time.Time) error
+	SoftDestroyMany(*sqlx.DB, *Model, time.Time) error
 	SelectOne(*sqlx.DB, *Model, Query) error
 	SelectMany(*sqlx.DB, *Model, Query) error
+	SelectRows(*sqlx.DB, *Model, Query) (*sqlx.Rows, error)
+	ScanRow(*sqlx.Rows, *Model) error
 	SQLView(*sqlx.DB, *Model, map[string]string) error
 	CreateTable(*sqlx.DB, *Model) error
 }
 
+// genericTranslateSQL replaces the `?` placeholders of a statement with the
+// bind parameters of the dialect. Placeholders in quoted strings are left
+// alone.
+func genericTranslateSQL(sql string, dialect string) string {
+	if values.Placeholder(dialect, 1) == "?" {
+		return sql
+	}
+	var sb strings.Builder
+	n, quoted := 0, false
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'':
+			quoted = !quoted
+		case c == '?' && !quoted:
+			n++
+			sb.WriteString(values.Placeholder(dialect, n))
+			continue
+		}
+		sb.WriteByte(sql[i])
+	}
+	return sb.String()
+}
+
+// quoteIdentifier quotes each part of a possibly qualified identifier with
+// the given quote character. Identifiers that are already quoted and `*` are
+// left untouched.
+func quoteIdentifier(name string, quote string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "*" || strings.HasPrefix(part, quote) {
+			continue
+		}
+		parts[i] = quote + strings.Replace(part, quote, quote+quote, -1) + quote
+	}
+	return strings.Join(parts, ".")
+}
+
 func genericCreate(db *sqlx.DB, model *Model) error {
-	model.setID(uuid.Must(uuid.NewV4()))
-	model.touchCreatedAt()
-	model.touchUpdatedAt()
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", model.TableName(), model.Columns(), model.TokenizedString())
 	stmt, err := db.PrepareNamed(query)
 	if err != nil {
@@ -45,19 +91,18 @@ func genericCreate(db *sqlx.DB, model *Model) error {
 	return errors.WithMessage(stmt.Close(), "failed to close statement")
 }
 
-func genericCreateMany(db *sqlx.DB, model *Model) error {
+func genericCreateMany(db *sqlx.DB, model *Model, dialect string) error {
 	if !model.isSlice() {
 		return errors.New("must pass slice")
 	}
 	values := []string{}
 	v := reflect.Indirect(reflect.ValueOf(model.Value))
 	for i := 0; i < v.Len(); i++ {
-		val := v.Index(i)
-		newModel := &Model{Value: val.Addr().Interface()}
-		newModel.setID(uuid.Must(uuid.NewV4()))
-		newModel.touchCreatedAt()
-		newModel.touchUpdatedAt()
-		values = append(values, StringTuple(newModel.Value))
+		tuple, err := literalTuple(v.Index(i).Addr().Interface(), model.namingStrategy(), dialect)
+		if err != nil {
+			return err
+		}
+		values = append(values, tuple)
 	}
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", model.TableName(), model.Columns(), strings.Join(values, ","))
 	if _, err := db.Exec(query); err != nil {
@@ -67,51 +112,166 @@ func genericCreateMany(db *sqlx.DB, model *Model) error {
 }
 
 func genericUpdate(db *sqlx.DB, model *Model) error {
-	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s", model.TableName(), model.UpdateString(), model.whereID())
+	return genericUpdateColumns(db, model, model.updateColumnSlice())
+}
+
+func genericUpdateColumns(db *sqlx.DB, model *Model, cols []string) error {
+	versionCol, versioned := model.versionColumn()
+	if versioned {
+		unversioned := []string{}
+		for _, col := range cols {
+			if col != versionCol {
+				unversioned = append(unversioned, col)
+			}
+		}
+		cols = unversioned
+	}
+	if len(cols) == 0 {
+		return errors.New("no columns to update")
+	}
+	pk, _, err := model.primaryKey()
+	if err != nil {
+		return err
+	}
+	set := model.updateStringFor(cols)
+	where := fmt.Sprintf("%s = :%s", pk, pk)
+	if versioned {
+		set = fmt.Sprintf("%s, %s = %s + 1", set, versionCol, versionCol)
+		where = fmt.Sprintf("%s AND %s = :%s", where, versionCol, versionCol)
+	}
+	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s", model.TableName(), set, where)
 	res, err := db.NamedExec(stmt, model.Value)
 	if err != nil {
 		return errors.Wrap(err, "updating record")
 	}
 	if numRows, _ := res.RowsAffected(); numRows == 0 {
+		if versioned {
+			return staleOrMissing(db, model)
+		}
 		return errors.New("query updated 0 rows")
 	}
+	if versioned {
+		model.incrementVersion()
+	}
 	return nil
 }
 
+// staleOrMissing tells apart a versioned update that matched no rows because
+// the record is gone from one that lost a race with another update
+func staleOrMissing(db *sqlx.DB, model *Model) error {
+	var count int
+	where, args, err := model.whereID()
+	if err != nil {
+		return err
+	}
+	stmt := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", model.TableName(), where)
+	if err := db.Get(&count, stmt, args...); err != nil {
+		return errors.Wrap(err, "checking record")
+	}
+	if count == 0 {
+		return errors.New("query updated 0 rows")
+	}
+	return errors.Wrapf(ErrStaleObject, "%s: %v", model.TableName(), model.ID())
+}
+
+func genericUpdateAll(db *sqlx.DB, model *Model, query Query, values map[string]interface{}) (int64, error) {
+	if len(values) == 0 {
+		return 0, errors.New("no columns to update")
+	}
+	sb, err := query.toSQLBuilder(model)
+	if err != nil {
+		return 0, err
+	}
+	stmt, args, err := sb.buildUpdateSQL(values)
+	if err != nil {
+		return 0, errors.Wrap(err, "updating records")
+	}
+	res, err := db.Exec(stmt, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "updating records")
+	}
+	return res.RowsAffected()
+}
+
 func genericDestroy(db *sqlx.DB, model *Model) error {
-	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s", model.TableName(), model.whereID())
-	if err := genericExec(db, stmt); err != nil {
+	where, args, err := model.whereID()
+	if err != nil {
+		return err
+	}
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s", model.TableName(), where)
+	if _, err := db.Exec(stmt, args...); err != nil {
 		return errors.Wrap(err, "deleting record")
 	}
 	return nil
 }
 
 func genericDestroyMany(db *sqlx.DB, model *Model) error {
-	ids := []string{}
-	if !model.isSlice() {
-		return errors.New("must supply slice")
-	}
-	v := reflect.Indirect(reflect.ValueOf(model.Value))
-	for i := 0; i < v.Len(); i++ {
-		val := v.Index(i)
-		newModel := &Model{Value: val.Addr().Interface()}
-		fbn, err := newModel.fieldByName("ID")
-		if err != nil {
-			return err
-		}
-		id, ok := fbn.Interface().(uuid.UUID)
-		if !ok {
-			return errors.New("error converting value to uuid")
-		}
-		ids = append(ids, fmt.Sprintf("'%s'", id))
+	where, args, err := model.whereIDs()
+	if err != nil {
+		return err
 	}
-	stmt := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", model.TableName(), strings.Join(ids, ","))
-	if _, err := db.Exec(stmt); err != nil {
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s", model.TableName(), where)
+	if _, err := db.Exec(stmt, args...); err != nil {
 		return errors.Wrap(err, "deleting records")
 	}
 	return nil
 }
 
+func genericSoftDestroy(db *sqlx.DB, model *Model, now time.Time) error {
+	col, ok := model.deletedAtColumn()
+	if !ok {
+		return errors.New("model does not have a DeletedAt field")
+	}
+	where, args, err := model.whereID()
+	if err != nil {
+		return err
+	}
+	model.touchDeletedAt(now)
+	stmt := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s", model.TableName(), col, where)
+	if _, err := db.Exec(stmt, append([]interface{}{now}, args...)...); err != nil {
+		return errors.Wrap(err, "soft deleting record")
+	}
+	return nil
+}
+
+func genericSoftDestroyMany(db *sqlx.DB, model *Model, now time.Time) error {
+	col, ok := model.deletedAtColumn()
+	if !ok {
+		return errors.New("model does not have a DeletedAt field")
+	}
+	where, args, err := model.whereIDs()
+	if err != nil {
+		return err
+	}
+	if err := model.iterate(func(m *Model) error {
+		m.touchDeletedAt(now)
+		return nil
+	}); err != nil {
+		return err
+	}
+	stmt := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s", model.TableName(), col, where)
+	if _, err := db.Exec(stmt, append([]interface{}{now}, args...)...); err != nil {
+		return errors.Wrap(err, "soft deleting records")
+	}
+	return nil
+}
+
+func genericDeleteWhere(db *sqlx.DB, model *Model, query Query) (int64, error) {
+	sb, err := query.toSQLBuilder(model)
+	if err != nil {
+		return 0, err
+	}
+	stmt, args, err := sb.buildDeleteSQL()
+	if err != nil {
+		return 0, errors.Wrap(err, "deleting records")
+	}
+	res, err := db.Exec(stmt, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "deleting records")
+	}
+	return res.RowsAffected()
+}
+
 func genericExec(db *sqlx.DB, stmt string) error {
 	if _, err := db.Exec(stmt); err != nil {
 		return errors.WithStack(err)
@@ -120,21 +280,87 @@ func genericExec(db *sqlx.DB, stmt string) error {
 }
 
 func genericSelectOne(db *sqlx.DB, model *Model, query Query) error {
-	sql, args := query.ToSQL(model)
-	if err := db.Get(model.Value, sql, args...); err != nil {
+	sql, args, err := query.ToSQLErr(model)
+	if err != nil {
+		return err
+	}
+	if err := db.GetContext(query.context(), model.Value, sql, args...); err != nil {
 		return err
 	}
 	return nil
 }
 
 func genericSelectMany(db *sqlx.DB, models *Model, query Query) error {
-	sql, args := query.ToSQL(models)
-	if err := db.Select(models.Value, sql, args...); err != nil {
+	sql, args, err := query.ToSQLErr(models)
+	if err != nil {
+		return err
+	}
+	if err := db.SelectContext(query.context(), models.Value, sql, args...); err != nil {
 		return err
 	}
 	return nil
 }
 
+func genericSelectRows(db *sqlx.DB, model *Model, query Query) (*sqlx.Rows, error) {
+	sql, args, err := query.ToSQLErr(model)
+	if err != nil {
+		return nil, err
+	}
+	return db.QueryxContext(query.context(), sql, args...)
+}
+
+func genericScanRow(rows *sqlx.Rows, model *Model) error {
+	return rows.StructScan(model.Value)
+}
+
+// selectOneWith is genericSelectOne with each row scanned by scan
+func selectOneWith(db *sqlx.DB, model *Model, query Query, scan func(*sqlx.Rows, *Model) error) error {
+	rows, err := genericSelectRows(db, model, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := scan(rows, model); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// selectManyWith is genericSelectMany with each row scanned by scan
+func selectManyWith(db *sqlx.DB, models *Model, query Query, scan func(*sqlx.Rows, *Model) error) error {
+	slice := reflect.Indirect(reflect.ValueOf(models.Value))
+	if slice.Kind() != reflect.Slice {
+		return errors.New("must pass pointer to slice")
+	}
+	elem := slice.Type().Elem()
+	isPtr := elem.Kind() == reflect.Ptr
+	if isPtr {
+		elem = elem.Elem()
+	}
+	rows, err := genericSelectRows(db, models, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		v := reflect.New(elem)
+		if err := scan(rows, &Model{Value: v.Interface(), naming: models.naming}); err != nil {
+			return err
+		}
+		if !isPtr {
+			v = v.Elem()
+		}
+		slice.Set(reflect.Append(slice, v))
+	}
+	return rows.Err()
+}
+
 func genericSQLView(db *sqlx.DB, models *Model, format map[string]string) error {
 	var (
 		err error
@@ -1,6 +1,15 @@
 package goala
 
-import "testing"
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/estenssoros/goala/nulls"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+)
 
 func TestCreateTable(t *testing.T) {
 	db, err := Connect()
@@ -13,3 +22,788 @@ func TestCreateTable(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+type softStruct struct {
+	ID        uuid.UUID  `db:"id"`
+	Name      string     `db:"name"`
+	DeletedAt nulls.Time `db:"deleted_at"`
+}
+
+func (s softStruct) TableName() string {
+	return "soft"
+}
+
+func newTestConnection(t *testing.T, models ...interface{}) *Connection {
+	db, err := Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.DB.SetMaxOpenConns(1)
+	for _, m := range models {
+		if err := db.CreateTable(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestSoftDestroy(t *testing.T) {
+	db := newTestConnection(t, &softStruct{})
+	defer db.Close()
+	records := []softStruct{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := db.Create(&records); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Destroy(&records[0]); err != nil {
+		t.Fatal(err)
+	}
+	if !records[0].DeletedAt.Valid {
+		t.Error("expected DeletedAt to be set")
+	}
+	count := func(q *Query) int {
+		found := []softStruct{}
+		if err := q.All(&found); err != nil {
+			t.Fatal(err)
+		}
+		return len(found)
+	}
+	if want, have := 2, count(Q(db)); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if want, have := 3, count(db.Unscoped()); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if want, have := 1, count(db.Where("name = ? OR name = ?", "a", "b")); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if err := db.DestroyMany(&records); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 0, count(Q(db)); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if err := db.HardDestroy(&records[1]); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 2, count(db.Unscoped()); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func TestUpdateColumns(t *testing.T) {
+	db := newTestConnection(t, &softStruct{})
+	defer db.Close()
+	records := []softStruct{{Name: "a"}, {Name: "b"}}
+	if err := db.Create(&records); err != nil {
+		t.Fatal(err)
+	}
+	records[0].Name = "changed"
+	records[0].DeletedAt = nulls.NewTime(time.Now())
+	if err := db.UpdateColumns(&records[0], "name"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpdateColumns(&records[0], "missing"); err == nil {
+		t.Error("expected error for missing column")
+	}
+	found := []softStruct{}
+	if err := db.Where("name = ?", "changed").All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(found); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	n, err := db.From(&softStruct{}).Where("name = ?", "b").UpdateAll(map[string]interface{}{"name": "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(1), n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if _, err := db.From(&softStruct{}).UpdateAll(map[string]interface{}{"name = 'x', id": "c"}); err == nil {
+		t.Error("expected error for missing column")
+	}
+	if err := db.UpdateExcept(&records[0], "missing"); err == nil {
+		t.Error("expected error for missing column")
+	}
+	if err := db.UpdateExcept(&records[0], "deleted_at"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteUnscopedByScope(t *testing.T) {
+	db := newTestConnection(t, &softStruct{})
+	defer db.Close()
+	RegisterScope(&softStruct{}, "unscopedForDelete", func(q *Query) *Query {
+		return q.Unscoped()
+	})
+	if err := db.Create(&[]softStruct{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Where("name = ?", "a").Scopes("unscopedForDelete").Delete(&softStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	found := []softStruct{}
+	if err := db.Unscoped().All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(found); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func TestSetBasedSliceArgs(t *testing.T) {
+	db := newTestConnection(t, &softStruct{})
+	defer db.Close()
+	records := []softStruct{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := db.Create(&records); err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{records[0].ID.String(), records[1].ID.String()}
+	n, err := db.From(&softStruct{}).Where("id in (?)", ids).UpdateAll(map[string]interface{}{"name": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(2), n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	n, err = db.Unscoped().Where("id in (?)", ids).Delete(&softStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(2), n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+type versionStruct struct {
+	ID      uuid.UUID `db:"id"`
+	Name    string    `db:"name"`
+	Version int       `db:"version"`
+}
+
+func (s versionStruct) TableName() string {
+	return "versioned"
+}
+
+func TestOptimisticLocking(t *testing.T) {
+	db := newTestConnection(t, &versionStruct{})
+	defer db.Close()
+	record := &versionStruct{Name: "a"}
+	if err := db.Create(record); err != nil {
+		t.Fatal(err)
+	}
+	stale := *record
+	record.Name = "b"
+	if err := db.Update(record); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, record.Version; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	stale.Name = "c"
+	if err := db.Update(&stale); !IsErrorStaleObject(err) {
+		t.Errorf("want stale object error, have: %v", err)
+	}
+	if err := db.HardDestroy(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Update(record); err == nil || IsErrorStaleObject(err) {
+		t.Errorf("want not found error, have: %v", err)
+	}
+}
+
+func TestQueryDelete(t *testing.T) {
+	db := newTestConnection(t, &softStruct{})
+	defer db.Close()
+	records := []softStruct{{Name: "a"}, {Name: "a"}, {Name: "b"}}
+	if err := db.Create(&records); err != nil {
+		t.Fatal(err)
+	}
+	n, err := db.Where("name = ?", "a").Delete(&softStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(2), n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	n, err = db.Where("name = ? OR name = ?", "a", "b").Delete(&softStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(1), n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	n, err = db.Unscoped().Where("name IN (?)", "a", "b").Delete(&softStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(3), n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if err := db.Create(&[]softStruct{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Q(db).Delete(&softStruct{}); err == nil {
+		t.Error("expected error for delete without where clauses")
+	}
+	if _, err := db.Where("name = ?", "a").Limit(1).Delete(&softStruct{}); err == nil {
+		t.Error("expected error for delete with limit")
+	}
+	n, err = db.Unscoped().DeleteAll(&softStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(2), n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+type hookStruct struct {
+	ID    uuid.UUID `db:"id"`
+	Name  string    `db:"name"`
+	Found bool
+}
+
+func (h hookStruct) TableName() string {
+	return "hooks"
+}
+
+func (h *hookStruct) BeforeCreate(c *Connection) error {
+	if h.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func (h *hookStruct) AfterFind(c *Connection) error {
+	h.Found = true
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	db := newTestConnection(t, &hookStruct{})
+	defer db.Close()
+	if err := db.Create(&hookStruct{}); err == nil {
+		t.Error("expected BeforeCreate to abort the insert")
+	}
+	if err := db.Create(&[]hookStruct{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	found := []hookStruct{}
+	if err := db.All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 2, len(found); want != have {
+		t.Fatalf("want: %d have: %d", want, have)
+	}
+	for _, h := range found {
+		if !h.Found {
+			t.Error("expected AfterFind to be called")
+		}
+	}
+	first := &hookStruct{}
+	if err := db.First(first); err != nil {
+		t.Fatal(err)
+	}
+	if !first.Found {
+		t.Error("expected AfterFind to be called")
+	}
+}
+
+type frozenClock time.Time
+
+func (f frozenClock) Now() time.Time {
+	return time.Time(f)
+}
+
+type timestampStruct struct {
+	ID       uuid.UUID  `db:"id"`
+	Inserted time.Time  `db:"inserted" timestamp:"created"`
+	Changed  nulls.Time `db:"changed" timestamp:"updated"`
+}
+
+func (s timestampStruct) TableName() string {
+	return "timestamps"
+}
+
+func TestTimestamps(t *testing.T) {
+	db := newTestConnection(t, &timestampStruct{})
+	defer db.Close()
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	db.Clock = frozenClock(created)
+	record := &timestampStruct{}
+	if err := db.Create(record); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := created, record.Inserted; !want.Equal(have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	updated := created.Add(time.Hour)
+	db.Clock = frozenClock(updated)
+	if err := db.Update(record); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := updated, record.Changed.Time; !record.Changed.Valid || !want.Equal(have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	if want, have := created, record.Inserted; !want.Equal(have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+}
+
+type stringIDStruct struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+}
+
+func (s stringIDStruct) TableName() string {
+	return "string_ids"
+}
+
+type intIDStruct struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func (s intIDStruct) TableName() string {
+	return "int_ids"
+}
+
+func TestIDGenerators(t *testing.T) {
+	db := newTestConnection(t, &stringIDStruct{}, &intIDStruct{})
+	defer db.Close()
+	db.IDGenerator = ULID
+	s := &stringIDStruct{Name: "a"}
+	if err := db.Create(s); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 26, len(s.ID); want != have {
+		t.Errorf("want: %d have: %d (%s)", want, have, s.ID)
+	}
+	db.IDGenerator = UUIDv7
+	s = &stringIDStruct{Name: "b"}
+	if err := db.Create(s); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := uuid.FromString(s.ID); err != nil || id.Version() != 7 {
+		t.Errorf("want uuid v7, have: %s", s.ID)
+	}
+	snowflake, err := NewSnowflake(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.IDGenerator = snowflake
+	ints := []intIDStruct{{Name: "a"}, {Name: "b"}}
+	if err := db.Create(&ints); err != nil {
+		t.Fatal(err)
+	}
+	if ints[0].ID == 0 || ints[1].ID <= ints[0].ID {
+		t.Errorf("want increasing ids, have: %d %d", ints[0].ID, ints[1].ID)
+	}
+	db.IDGenerator = CallerProvided
+	manual := &intIDStruct{ID: 42, Name: "c"}
+	if err := db.Create(manual); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(42), manual.ID; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func TestUpdateAndDestroyByID(t *testing.T) {
+	db := newTestConnection(t, &stringIDStruct{}, &intIDStruct{})
+	defer db.Close()
+	snowflake, err := NewSnowflake(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.IDGenerator = snowflake
+	ints := []intIDStruct{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := db.Create(&ints); err != nil {
+		t.Fatal(err)
+	}
+	ints[0].Name = "changed"
+	if err := db.Update(&ints[0]); err != nil {
+		t.Fatal(err)
+	}
+	found := &intIDStruct{}
+	if err := db.Where("id = ?", ints[0].ID).First(found); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := "changed", found.Name; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if err := db.Destroy(&ints[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DestroyMany(&[]intIDStruct{ints[1]}); err != nil {
+		t.Fatal(err)
+	}
+	remaining := []intIDStruct{}
+	if err := db.All(&remaining); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(remaining); want != have {
+		t.Fatalf("want: %d have: %d", want, have)
+	}
+	if want, have := ints[2].ID, remaining[0].ID; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+
+	db.IDGenerator = CallerProvided
+	strs := []stringIDStruct{{ID: "o'brien", Name: "a"}, {ID: "x' OR '1'='1", Name: "b"}}
+	if err := db.Create(&strs); err != nil {
+		t.Fatal(err)
+	}
+	strs[0].Name = "changed"
+	if err := db.Update(&strs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Destroy(&strs[1]); err != nil {
+		t.Fatal(err)
+	}
+	left := []stringIDStruct{}
+	if err := db.All(&left); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(left); want != have {
+		t.Fatalf("want: %d have: %d", want, have)
+	}
+	if want, have := "changed", left[0].Name; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if err := db.DestroyMany(&left); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.All(&left); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 0, len(left); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+type NamedPerson struct {
+	ID        uuid.UUID
+	FirstName string
+	Nickname  string `db:"nick"`
+	Secret    string `db:"-"`
+}
+
+func TestNamingStrategy(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	db.SetNamingStrategy(SnakeCase{TablePrefix: "app_", PluralTables: true})
+	m := db.newModel(&NamedPerson{})
+	if want, have := "app_named_persons", m.TableName(); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := "id,first_name,nick", m.Columns(); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if err := db.CreateTable(&NamedPerson{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateMany(&[]NamedPerson{{FirstName: "mark", Nickname: "m", Secret: "s"}}); err != nil {
+		t.Fatal(err)
+	}
+	found := &NamedPerson{}
+	if err := db.Where("first_name = ?", "mark").First(found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Nickname != "m" || found.Secret != "" {
+		t.Errorf("unexpected record: %+v", found)
+	}
+}
+
+func TestDefaultNamingStrategy(t *testing.T) {
+	DefaultNamingStrategy = SnakeCase{}
+	defer func() { DefaultNamingStrategy = nil }()
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&NamedPerson{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&NamedPerson{FirstName: "mark", Nickname: "m"}); err != nil {
+		t.Fatal(err)
+	}
+	found := &NamedPerson{}
+	if err := db.Where("first_name = ?", "mark").First(found); err != nil {
+		t.Fatal(err)
+	}
+	if found.FirstName != "mark" || found.Nickname != "m" {
+		t.Errorf("unexpected record: %+v", found)
+	}
+	all := []NamedPerson{}
+	if err := db.All(&all); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(all); want != have || all[0].FirstName != "mark" {
+		t.Errorf("unexpected records: %+v", all)
+	}
+}
+
+type Timestamps struct {
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type embeddedStruct struct {
+	ID   uuid.UUID `db:"id"`
+	Name *string   `db:"name"`
+	Age  *int      `db:"age"`
+	Timestamps
+}
+
+func (embeddedStruct) TableName() string { return "embedded" }
+
+func TestEmbeddedAndPointerFields(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	m := db.newModel(&embeddedStruct{})
+	if want, have := "id,name,age,created_at,updated_at", m.Columns(); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := []int{UUIDType, NullsStringType, NullsIntType, TimeType, TimeType}, DataTypes(&embeddedStruct{}); !reflect.DeepEqual(want, have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	if err := db.CreateTable(&embeddedStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	name := "mark"
+	records := []embeddedStruct{{Name: &name}}
+	if err := db.CreateMany(&records); err != nil {
+		t.Fatal(err)
+	}
+	if records[0].CreatedAt.IsZero() {
+		t.Error("want created_at to be set through the embedded struct")
+	}
+	found := &embeddedStruct{}
+	if err := db.First(found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Name == nil || *found.Name != name || found.Age != nil {
+		t.Errorf("unexpected record: %+v", found)
+	}
+	if found.CreatedAt.IsZero() {
+		t.Errorf("want created_at to be read back, have: %+v", found)
+	}
+}
+
+type moreNullsStruct struct {
+	ID      uuid.UUID       `db:"id"`
+	Int32   nulls.Int32     `db:"int32"`
+	Int64   nulls.Int64     `db:"int64"`
+	UInt32  nulls.UInt32    `db:"uint32"`
+	Float32 nulls.Float32   `db:"float32"`
+	Byte    nulls.Byte      `db:"byte"`
+	Bytes   nulls.ByteSlice `db:"bytes"`
+	Ref     nulls.UUID      `db:"ref"`
+}
+
+func (moreNullsStruct) TableName() string { return "more_nulls" }
+
+func TestMoreNullsTypes(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&moreNullsStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	ref := uuid.Must(uuid.NewV4())
+	valid := moreNullsStruct{
+		Int32:   nulls.NewInt32(-32),
+		Int64:   nulls.NewInt64(1 << 40),
+		UInt32:  nulls.NewUInt32(4000000000),
+		Float32: nulls.NewFloat32(1.5),
+		Byte:    nulls.NewByte(7),
+		Bytes:   nulls.NewByteSlice([]byte("raw")),
+		Ref:     nulls.NewUUID(ref),
+	}
+	records := []moreNullsStruct{valid, {}}
+	if err := db.CreateMany(&records); err != nil {
+		t.Fatal(err)
+	}
+	found := &moreNullsStruct{}
+	if err := db.Where("id = ?", records[0].ID).First(found); err != nil {
+		t.Fatal(err)
+	}
+	valid.ID = records[0].ID
+	if !reflect.DeepEqual(valid, *found) {
+		t.Errorf("want: %+v have: %+v", valid, *found)
+	}
+	empty := &moreNullsStruct{}
+	if err := db.Where("id = ?", records[1].ID).First(empty); err != nil {
+		t.Fatal(err)
+	}
+	if empty.Int32.Valid || empty.Int64.Valid || empty.UInt32.Valid || empty.Float32.Valid || empty.Byte.Valid || empty.Bytes.Valid || empty.Ref.Valid {
+		t.Errorf("want every field to be null, have: %+v", empty)
+	}
+}
+
+type genericNullsStruct struct {
+	ID      uuid.UUID             `db:"id"`
+	Name    nulls.Null[string]    `db:"name"`
+	Age     nulls.Null[int16]     `db:"age"`
+	Count   nulls.Null[int64]     `db:"count"`
+	Score   nulls.Null[float64]   `db:"score"`
+	Ref     nulls.Null[uuid.UUID] `db:"ref"`
+	Missing nulls.Null[string]    `db:"missing"`
+}
+
+func (genericNullsStruct) TableName() string { return "generic_nulls" }
+
+func TestGenericNull(t *testing.T) {
+	want := []int{UUIDType, NullsStringType, NullsIntType, NullsInt64Type, NullsFloatType, NullsUUIDType, NullsStringType}
+	if have := DataTypes(&genericNullsStruct{}); !reflect.DeepEqual(want, have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&genericNullsStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	record := genericNullsStruct{
+		Name:  nulls.NewNull("mark"),
+		Age:   nulls.NewNull[int16](42),
+		Count: nulls.NewNull[int64](7),
+		Score: nulls.NewNull(1.5),
+		Ref:   nulls.NewNull(uuid.Must(uuid.NewV4())),
+	}
+	if err := db.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+	found := genericNullsStruct{}
+	if err := db.First(&found); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(record, found) {
+		t.Errorf("want: %+v have: %+v", record, found)
+	}
+	if want, have := "none", found.Missing.OrElse("none"); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	data, err := json.Marshal(found)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := genericNullsStruct{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(found, decoded) {
+		t.Errorf("want: %+v have: %+v", found, decoded)
+	}
+	age := nulls.Null[int16]{}
+	if err := age.UnmarshalText([]byte("12")); err != nil || age.V != 12 || !age.Valid {
+		t.Errorf("want valid 12, have: %+v %v", age, err)
+	}
+	if text, _ := (nulls.Null[int16]{}).MarshalText(); string(text) != "null" {
+		t.Errorf("want: null have: %s", text)
+	}
+}
+
+type rawTimeStruct struct {
+	ID   int        `db:"id"`
+	Seen nulls.Time `db:"seen"`
+}
+
+func TestNullsTimeScan(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if _, err := db.DB.Exec("CREATE TABLE raw_times (id INT, seen)"); err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	values := []interface{}{
+		"2020-01-02T03:04:05Z",
+		"2020-01-02 03:04:05",
+		"2020-01-02 03:04:05.000",
+		want.Unix(),
+		want.UnixMilli(),
+		nil,
+	}
+	for i, v := range values {
+		if _, err := db.DB.Exec("INSERT INTO raw_times (id, seen) VALUES (?, ?)", i, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows := []rawTimeStruct{}
+	if err := db.DB.Select(&rows, "SELECT id, seen FROM raw_times ORDER BY id"); err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows[:len(rows)-1] {
+		if !row.Seen.Valid || !row.Seen.Time.Equal(want) {
+			t.Errorf("%v: want: %v have: %+v", values[i], want, row.Seen)
+		}
+	}
+	if rows[len(rows)-1].Seen.Valid {
+		t.Errorf("want null, have: %+v", rows[len(rows)-1].Seen)
+	}
+	if _, err := db.DB.Exec("INSERT INTO raw_times (id, seen) VALUES (?, ?)", 99, "yesterday"); err != nil {
+		t.Fatal(err)
+	}
+	bad := rawTimeStruct{}
+	if err := db.DB.Get(&bad, "SELECT id, seen FROM raw_times WHERE id = 99"); err == nil {
+		t.Error("want an error for an unparseable time")
+	}
+}
+
+type plainTimeStruct struct {
+	ID   int        `db:"id"`
+	Seen time.Time  `db:"seen"`
+	Last *time.Time `db:"last"`
+}
+
+func (plainTimeStruct) TableName() string {
+	return "plain_times"
+}
+
+func TestPlainTimeScan(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if _, err := db.DB.Exec("CREATE TABLE plain_times (id INT, seen NUMERIC, last NUMERIC)"); err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	values := []interface{}{"2020-01-02 03:04:05", want.Unix(), want.UnixMilli()}
+	for i, v := range values {
+		if _, err := db.DB.Exec("INSERT INTO plain_times (id, seen, last) VALUES (?, ?, ?)", i, v, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows := []plainTimeStruct{}
+	if err := db.Order("id").All(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := len(values), len(rows); want != have {
+		t.Fatalf("want: %d have: %d", want, have)
+	}
+	for i, row := range rows {
+		if !row.Seen.Equal(want) || row.Last != nil {
+			t.Errorf("%v: want: %v have: %+v", values[i], want, row)
+		}
+	}
+	one := &plainTimeStruct{}
+	if err := db.Where("id = ?", 1).First(one); err != nil {
+		t.Fatal(err)
+	}
+	if !one.Seen.Equal(want) {
+		t.Errorf("want: %v have: %v", want, one.Seen)
+	}
+	n := 0
+	if err := db.Each(&plainTimeStruct{}, func() error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := len(values), n; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if _, err := db.DB.Exec("INSERT INTO plain_times (id, seen) VALUES (?, ?)", 99, "yesterday"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Where("id = ?", 99).First(&plainTimeStruct{}); err == nil {
+		t.Error("want an error for an unparseable time")
+	}
+}
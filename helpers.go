@@ -4,36 +4,56 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"math"
 	"reflect"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/estenssoros/goala/nulls"
+	"github.com/estenssoros/goala/values"
 	interpol "github.com/imkira/go-interpol"
 	"github.com/pkg/errors"
 	"github.com/satori/go.uuid"
 )
 
 const (
-	StringType      = 0
-	IntType         = iota
-	FloatType       = iota
-	BoolType        = iota
-	TimeType        = iota
-	UUIDType        = iota
-	NullsStringType = iota
-	NullsIntType    = iota
-	NullsFloatType  = iota
-	NullsTimeType   = iota
-	NullsBoolType   = iota
+	StringType         = 0
+	IntType            = iota
+	FloatType          = iota
+	BoolType           = iota
+	TimeType           = iota
+	UUIDType           = iota
+	NullsStringType    = iota
+	NullsIntType       = iota
+	NullsFloatType     = iota
+	NullsTimeType      = iota
+	NullsBoolType      = iota
+	ValuerType         = iota
+	BytesType          = iota
+	JSONType           = iota
+	NullsInt32Type     = iota
+	NullsInt64Type     = iota
+	NullsUInt32Type    = iota
+	NullsFloat32Type   = iota
+	NullsByteType      = iota
+	NullsByteSliceType = iota
+	NullsUUIDType      = iota
 )
 
 func IsErrorNoRows(err error) bool {
 	return strings.Contains(err.Error(), "no rows in result set")
 }
 
+// ErrStaleObject is returned when updating a model with a version column
+// whose version no longer matches the one in the database.
+var ErrStaleObject = errors.New("stale object")
+
+// IsErrorStaleObject reports whether an update failed because the model was
+// changed by someone else since it was loaded
+func IsErrorStaleObject(err error) bool {
+	return errors.Cause(err) == ErrStaleObject
+}
+
 // EscapeString replaces error causing characters in  a string
 func EscapeString(sql string) string {
 	dest := make([]byte, 0, 2*len(sql))
@@ -72,268 +92,276 @@ func EscapeString(sql string) string {
 	return string(dest)
 }
 
-// StringSlice converts all fields of a struct to a string slice
+// StringSlice converts all fields of a struct to a string slice. Values
+// are formatted as by `values.Value.String`: times as
+// `2006-01-02 15:04:05.999999999-07:00`, floats in their shortest exact
+// form, booleans as true and false and NULL for null values.
 func (c *Connection) StringSlice(v interface{}) []string {
-	return StringSlice(v)
+	return stringSlice(v, c.newModel(v).namingStrategy())
 }
 
+// DataTypes returns the data type of every column of a struct
 func DataTypes(v interface{}) []int {
-	fields := reflect.TypeOf(v)
-	values := reflect.ValueOf(v)
-	if values.Kind() == reflect.Ptr {
-		values = values.Elem()
-		fields = fields.Elem()
-	}
-	dTypes := make([]int, fields.NumField())
-	for i := 0; i < fields.NumField(); i++ {
-		field := fields.Field(i)
-		if dbTag := field.Tag.Get("db"); dbTag == "" {
-			continue
+	return dataTypes(v, DefaultNamingStrategy)
+}
+
+func dataTypes(v interface{}, ns NamingStrategy) []int {
+	return metadataFor(reflect.TypeOf(v), ns).dataTypes()
+}
+
+// nullableDataTypes maps the data type of a value to the one of a pointer
+// to it
+var nullableDataTypes = map[int]int{
+	StringType: NullsStringType,
+	IntType:    NullsIntType,
+	FloatType:  NullsFloatType,
+	BoolType:   NullsBoolType,
+	TimeType:   NullsTimeType,
+}
+
+// lookupDataType returns the data type of a column field. Pointer fields
+// are nullable.
+func lookupDataType(col columnField) (int, bool) {
+	dType, ok := typeDataType(col.Type())
+	if ok && col.Nullable() {
+		if n, ok := nullableDataTypes[dType]; ok {
+			return n, true
 		}
-		value := values.Field(i)
-		switch value.Kind() {
-		case reflect.String:
-			dTypes[i] = StringType
-		case reflect.Int, reflect.Int16, reflect.Int8, reflect.Int32, reflect.Int64:
-			dTypes[i] = IntType
-		case reflect.Float64, reflect.Float32:
-			dTypes[i] = FloatType
-		case reflect.Bool:
-			dTypes[i] = BoolType
-		default:
-			switch field.Type {
-			case reflect.TypeOf(time.Time{}):
-				dTypes[i] = TimeType
-			case reflect.TypeOf(uuid.UUID{}):
-				dTypes[i] = UUIDType
-			case reflect.TypeOf(nulls.Int{}):
-				dTypes[i] = NullsIntType
-			case reflect.TypeOf(nulls.String{}):
-				dTypes[i] = NullsStringType
-			case reflect.TypeOf(nulls.Float64{}):
-				dTypes[i] = NullsFloatType
-			case reflect.TypeOf(nulls.Time{}):
-				dTypes[i] = NullsTimeType
-			case reflect.TypeOf(nulls.Bool{}):
-				dTypes[i] = NullsBoolType
-			default:
-				panic(fmt.Sprintf("unknown field type: %v", field.Type))
-			}
+	}
+	return dType, ok
+}
+
+func typeDataType(t reflect.Type) (int, bool) {
+	if ct, ok := lookupColumnType(t); ok {
+		return ct.DataType, true
+	}
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return TimeType, true
+	case reflect.TypeOf(uuid.UUID{}):
+		return UUIDType, true
+	case reflect.TypeOf(nulls.Int{}):
+		return NullsIntType, true
+	case reflect.TypeOf(nulls.String{}):
+		return NullsStringType, true
+	case reflect.TypeOf(nulls.Float64{}):
+		return NullsFloatType, true
+	case reflect.TypeOf(nulls.Time{}):
+		return NullsTimeType, true
+	case reflect.TypeOf(nulls.Bool{}):
+		return NullsBoolType, true
+	case reflect.TypeOf(nulls.Int32{}):
+		return NullsInt32Type, true
+	case reflect.TypeOf(nulls.Int64{}):
+		return NullsInt64Type, true
+	case reflect.TypeOf(nulls.UInt32{}):
+		return NullsUInt32Type, true
+	case reflect.TypeOf(nulls.Float32{}):
+		return NullsFloat32Type, true
+	case reflect.TypeOf(nulls.Byte{}):
+		return NullsByteType, true
+	case reflect.TypeOf(nulls.ByteSlice{}):
+		return NullsByteSliceType, true
+	case reflect.TypeOf(nulls.UUID{}):
+		return NullsUUIDType, true
+	}
+	if t.Implements(nullValueType) {
+		return nullDataType(reflect.Zero(t).Interface().(valueTyper).ValueType())
+	}
+	if isValuer(t) {
+		return ValuerType, true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return StringType, true
+	case reflect.Int, reflect.Int16, reflect.Int8, reflect.Int32, reflect.Int64:
+		return IntType, true
+	case reflect.Float64, reflect.Float32:
+		return FloatType, true
+	case reflect.Bool:
+		return BoolType, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return BytesType, true
 		}
 	}
-	return dTypes
+	return -1, false
+}
+
+// valueTyper is implemented by `nulls.Null` to expose its type parameter
+type valueTyper interface {
+	ValueType() reflect.Type
+}
+
+var nullValueType = reflect.TypeOf((*valueTyper)(nil)).Elem()
+
+// nullDataType returns the data type of a `nulls.Null` holding values of
+// type t
+func nullDataType(t reflect.Type) (int, bool) {
+	switch t.Kind() {
+	case reflect.Int32:
+		return NullsInt32Type, true
+	case reflect.Int64:
+		return NullsInt64Type, true
+	case reflect.Uint32:
+		return NullsUInt32Type, true
+	case reflect.Float32:
+		return NullsFloat32Type, true
+	case reflect.Uint8:
+		return NullsByteType, true
+	}
+	dType, ok := typeDataType(t)
+	if !ok {
+		return dType, false
+	}
+	switch dType {
+	case BytesType:
+		return NullsByteSliceType, true
+	case UUIDType:
+		return NullsUUIDType, true
+	}
+	if n, ok := nullableDataTypes[dType]; ok {
+		return n, true
+	}
+	return dType, true
 }
 
-// StringSlice converts all fields of a struct to a string slice
+// columnSQLType returns the column type a registered type or ColumnTyper
+// asks for
+func columnSQLType(t reflect.Type) string {
+	ct, _ := lookupColumnType(t)
+	return ct.SQL
+}
+
+// StringSlice converts all fields of a struct to a string slice. Values
+// are formatted as by `values.Value.String`: times as
+// `2006-01-02 15:04:05.999999999-07:00`, floats in their shortest exact
+// form, booleans as true and false and NULL for null values.
 func StringSlice(v interface{}) []string {
+	return stringSlice(v, DefaultNamingStrategy)
+}
+
+func stringSlice(v interface{}, ns NamingStrategy) []string {
+	vals, err := fieldValues(v, ns)
+	if err != nil {
+		panic(err.Error())
+	}
+	stringSlice := make([]string, len(vals))
+	for i, val := range vals {
+		stringSlice[i] = val.String()
+	}
+	return stringSlice
+}
+
+// fieldValues returns the typed value of every column of a struct. Fields
+// behind nil pointers are NULL.
+func fieldValues(v interface{}, ns NamingStrategy) ([]values.Value, error) {
 	fields := reflect.TypeOf(v)
-	values := reflect.ValueOf(v)
-	if values.Kind() == reflect.Ptr {
-		values = values.Elem()
+	vals := reflect.ValueOf(v)
+	if vals.Kind() == reflect.Ptr {
+		vals = vals.Elem()
 		fields = fields.Elem()
 	}
-	numFields := fields.NumField()
-	stringSlice := make([]string, numFields)
-	for i := 0; i < numFields; i++ {
-		field := fields.Field(i)
-		value := values.Field(i)
-		switch value.Kind() {
-		case reflect.String:
-			v := value.String()
-			stringSlice[i] = v
-		case reflect.Int, reflect.Int16, reflect.Int8, reflect.Int32, reflect.Int64:
-			v := value.Int()
-			stringSlice[i] = fmt.Sprintf("%d", v)
-		case reflect.Float64, reflect.Float32:
-			v := value.Float()
-			stringSlice[i] = fmt.Sprintf("%f", v)
-		case reflect.Bool:
-			v := value.Bool()
-			stringSlice[i] = fmt.Sprintf("%v", v)
-		default:
-			switch field.Type {
-			case reflect.TypeOf(time.Time{}):
-				v := value.Interface().(time.Time)
-				stringSlice[i] = v.Format("2006-01-02 15:04:05")
-			case reflect.TypeOf(uuid.UUID{}):
-				v := value.Interface().(uuid.UUID)
-				stringSlice[i] = fmt.Sprintf("%s", v.String())
-			case reflect.TypeOf(nulls.Int{}):
-				v := value.Interface().(nulls.Int)
-				if v.Valid {
-					stringSlice[i] = fmt.Sprintf("%d", v.Int)
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			case reflect.TypeOf(nulls.String{}):
-				v := value.Interface().(nulls.String)
-				if v.Valid {
-					stringSlice[i] = fmt.Sprintf("'%s'", v.String)
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			case reflect.TypeOf(nulls.Float64{}):
-				v := value.Interface().(nulls.Float64)
-				if v.Valid {
-					stringSlice[i] = fmt.Sprintf("%f", v.Float64)
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			case reflect.TypeOf(nulls.Time{}):
-				v := value.Interface().(nulls.Time)
-				if v.Valid {
-					stringSlice[i] = v.Time.Format("'2006-01-02 15:04:05'")
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			case reflect.TypeOf(nulls.Bool{}):
-				v := value.Interface().(nulls.Bool)
-				if v.Valid {
-					if v.Bool {
-						stringSlice[i] = "1"
-					} else {
-						stringSlice[i] = "0"
-					}
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			default:
-				panic(fmt.Sprintf("unknown field type: %v", field.Type))
-			}
+	cols := columnFields(fields, ns)
+	out := make([]values.Value, len(cols))
+	for i, col := range cols {
+		value, ok := fieldValue(vals, col)
+		if !ok || (value.Kind() == reflect.Ptr && value.IsNil()) {
+			out[i] = values.Null()
+			continue
+		}
+		dv, err := driverValue(reflect.Indirect(value))
+		if err != nil {
+			return nil, errors.Wrapf(err, "converting field %s", col.Name)
+		}
+		val, err := values.Of(dv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "converting field %s", col.Name)
 		}
+		out[i] = val
 	}
-	return stringSlice
+	return out, nil
 }
 
-// StringTuple converts struct to MySQL compatible string tuple
+// StringTuple converts struct to MySQL compatible string tuple. Values are
+// MySQL literals as by `values.Value.Literal`: escaped quoted strings,
+// times in UTC as `2006-01-02 15:04:05.999999`, floats in their shortest
+// exact form and booleans as 1 and 0.
 func StringTuple(c interface{}) string {
-	fields := reflect.TypeOf(c)
-	values := reflect.ValueOf(c)
-	if values.Kind() == reflect.Ptr {
-		values = values.Elem()
-		fields = fields.Elem()
+	return stringTuple(c, DefaultNamingStrategy)
+}
+
+func stringTuple(c interface{}, ns NamingStrategy) string {
+	tuple, err := literalTuple(c, ns, values.MySQL)
+	if err != nil {
+		panic(err.Error())
 	}
-	numFields := fields.NumField()
-	stringSlice := make([]string, numFields)
-	for i := 0; i < numFields; i++ {
-		field := fields.Field(i)
-		value := values.Field(i)
-		switch value.Kind() {
-		case reflect.String:
-			stringSlice[i] = fmt.Sprintf("'%s'", EscapeString(value.String()))
-		case reflect.Int, reflect.Int16, reflect.Int8, reflect.Int32, reflect.Int64:
-			stringSlice[i] = fmt.Sprintf("%d", value.Int())
-		case reflect.Float64, reflect.Float32:
-			v := value.Float()
-			if math.IsNaN(v) {
-				stringSlice[i] = "NULL"
-			} else {
-				stringSlice[i] = fmt.Sprintf("%f", v)
-			}
-		case reflect.Bool:
-			stringSlice[i] = fmt.Sprintf("%v", value.Bool())
-		default:
-			switch field.Type {
-			case reflect.TypeOf(time.Time{}):
-				v := value.Interface().(time.Time)
-				stringSlice[i] = v.Format("'2006-01-02 15:04:05'")
-			case reflect.TypeOf(uuid.UUID{}):
-				v := value.Interface().(uuid.UUID)
-				stringSlice[i] = fmt.Sprintf("'%s'", v.String())
-			case reflect.TypeOf(nulls.Int{}):
-				v := value.Interface().(nulls.Int)
-				if v.Valid {
-					stringSlice[i] = fmt.Sprintf("%d", v.Int)
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			case reflect.TypeOf(nulls.String{}):
-				v := value.Interface().(nulls.String)
-				if v.Valid {
-					stringSlice[i] = fmt.Sprintf("'%s'", EscapeString(v.String))
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			case reflect.TypeOf(nulls.Float64{}):
-				v := value.Interface().(nulls.Float64)
-				if v.Valid {
-					if math.IsNaN(v.Float64) {
-						stringSlice[i] = "NULL"
-					} else {
-						stringSlice[i] = fmt.Sprintf("%f", v.Float64)
-					}
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			case reflect.TypeOf(nulls.Time{}):
-				v := value.Interface().(nulls.Time)
-				if v.Valid {
-					stringSlice[i] = v.Time.Format("'2006-01-02 15:04:05'")
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			case reflect.TypeOf(nulls.Bool{}):
-				v := value.Interface().(nulls.Bool)
-				if v.Valid {
-					if v.Bool {
-						stringSlice[i] = "1"
-					} else {
-						stringSlice[i] = "0"
-					}
-				} else {
-					stringSlice[i] = "NULL"
-				}
-			default:
-				panic(fmt.Sprintf("unknown field type: %v", field.Type))
-			}
-		}
+	return tuple
+}
+
+// literalTuple renders the columns of a struct as a tuple of literals for a
+// dialect
+func literalTuple(c interface{}, ns NamingStrategy, dialect string) (string, error) {
+	vals, err := fieldValues(c, ns)
+	if err != nil {
+		return "", err
+	}
+	literals := make([]string, len(vals))
+	for i, val := range vals {
+		literals[i] = val.Literal(dialect)
 	}
-	return fmt.Sprintf("(%s)", strings.Join(stringSlice, ","))
+	return fmt.Sprintf("(%s)", strings.Join(literals, ",")), nil
 }
 
 func createSchema(v interface{}) (*Schema, error) {
-	tableName, err := TableName(v)
-	if err != nil {
-		return nil, errors.Wrap(err, "create schema")
+	return (&Model{Value: v}).CreateSchema()
+}
+
+// newSchema creates the schema of the table of a model
+func newSchema(m *Model) (*Schema, error) {
+	if _, ok := m.Value.(TableNameAble); !ok && m.namingStrategy() == nil {
+		return nil, errors.Wrap(errors.New("missing tablename method on struct"), "create schema")
 	}
 	schema := &Schema{
-		TableName: tableName,
+		TableName: m.TableName(),
 		Columns:   make(map[string]*Column),
 		Order:     []string{},
 	}
-	dTypes := DataTypes(v)
-	names := ColumnNames(v)
+	meta := m.metadata()
+	dTypes := meta.dataTypes()
+	names := meta.Columns
 	if len(dTypes) == 0 {
 		return nil, errors.New("no data marked for schema. did you include the db tag?")
 	}
 	if len(dTypes) != len(names) {
 		return nil, errors.New("create schema: dimension: names does not match data types")
 	}
-	for i, t := range DataTypes(v) {
+	for i, t := range dTypes {
 		schema.AddColumn(names[i], t)
+		if sqlType := meta.SQLTypes[i]; sqlType != "" {
+			schema.Columns[names[i]].SQLType = sqlType
+		}
+		if def, ok := meta.Fields[i].Field.Tag.Lookup("default"); ok {
+			if err := schema.Columns[names[i]].ParseDefault(def); err != nil {
+				return nil, errors.Wrap(err, "create schema")
+			}
+		}
 	}
 	return schema, nil
 }
 
 // ColumnNames creates a slice of names from a struct
 func (c *Connection) ColumnNames(v interface{}) []string {
-	return ColumnNames(v)
+	return columnNames(v, c.newModel(v).namingStrategy())
 }
 
 // ColumnNames creates a slice of names from a struct
 func ColumnNames(v interface{}) []string {
-	fields := reflect.TypeOf(v)
-	values := reflect.ValueOf(v)
-	if values.Kind() == reflect.Ptr {
-		values = values.Elem()
-		fields = fields.Elem()
-	}
-	cols := make([]string, values.NumField())
-	for i := 0; i < values.NumField(); i++ {
-		f := fields.Field(i)
-		cols[i] = f.Tag.Get("db")
-	}
-	return cols
+	return columnNames(v, DefaultNamingStrategy)
+}
+
+func columnNames(v interface{}, ns NamingStrategy) []string {
+	return append([]string{}, metadataFor(reflect.TypeOf(v), ns).Columns...)
 }
 
 // searches for a model field. returns an error if non exists
@@ -383,14 +411,9 @@ func TruncateStmt(t interface{}) string {
 // rows.Scan(seaspandb.Scanner(&m)...)
 func Scanner(u interface{}) []interface{} {
 	val := reflect.ValueOf(u).Elem()
-	typ := val.Type()
 	v := []interface{}{}
-	for i := 0; i < val.NumField(); i++ {
-		typeField := typ.Field(i)
-		if typeField.Tag.Get("db") == "" {
-			continue
-		}
-		valueField := val.Field(i)
+	for _, col := range columnFields(val.Type(), DefaultNamingStrategy) {
+		valueField := fieldValueAlloc(val, col)
 		v = append(v, valueField.Addr().Interface())
 	}
 	return v
@@ -409,15 +432,7 @@ func ScanRow(rows *sql.Rows, v interface{}) error {
 
 // CSVHeaders creates a slice of headers from a struct
 func CSVHeaders(c interface{}) []string {
-	structValue := reflect.ValueOf(c)
-	structType := structValue.Type()
-	numFields := structValue.NumField()
-	cols := make([]string, numFields)
-	for i := 0; i < numFields; i++ {
-		f := structType.Field(i)
-		cols[i] = f.Tag.Get("db")
-	}
-	return cols
+	return ColumnNames(c)
 }
 
 // MustFormatMap formats a string from a map or panics
@@ -431,21 +446,7 @@ func MustFormatMap(s string, m map[string]string) string {
 
 // InsertIgnore crafts insert ignore statement fro mstruct tags
 func InsertIgnore(t table) string {
-	structValue := reflect.ValueOf(t)
-	structType := structValue.Type()
-
 	stmt := "INSERT IGNORE INTO `%s` (%s) VALUES "
-
-	numFields := structValue.NumField()
-	cols := make([]string, numFields)
-
-	for i := 0; i < numFields; i++ {
-		f := structType.Field(i)
-		colName := f.Tag.Get("db")
-		if colName == "" {
-			colName = ToSnakeCase(f.Name)
-		}
-		cols[i] = fmt.Sprintf("`%s`", colName)
-	}
-	return fmt.Sprintf(stmt, t.TableName(), strings.Join(cols, ","))
+	m := &Model{Value: t}
+	return fmt.Sprintf(stmt, t.TableName(), strings.Join(m.ColumnSliceSafe(), ","))
 }
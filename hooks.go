@@ -0,0 +1,150 @@
+package goala
+
+// BeforeSaveable callback will be called before a record is
+// created or updated in the database.
+type BeforeSaveable interface {
+	BeforeSave(*Connection) error
+}
+
+// AfterSaveable callback will be called after a record is
+// created or updated in the database.
+type AfterSaveable interface {
+	AfterSave(*Connection) error
+}
+
+// BeforeCreateable callback will be called before a record is
+// created in the database.
+type BeforeCreateable interface {
+	BeforeCreate(*Connection) error
+}
+
+// AfterCreateable callback will be called after a record is
+// created in the database.
+type AfterCreateable interface {
+	AfterCreate(*Connection) error
+}
+
+// BeforeUpdateable callback will be called before a record is
+// updated in the database.
+type BeforeUpdateable interface {
+	BeforeUpdate(*Connection) error
+}
+
+// AfterUpdateable callback will be called after a record is
+// updated in the database.
+type AfterUpdateable interface {
+	AfterUpdate(*Connection) error
+}
+
+// BeforeDestroyable callback will be called before a record is
+// destroyed in the database.
+type BeforeDestroyable interface {
+	BeforeDestroy(*Connection) error
+}
+
+// AfterDestroyable callback will be called after a record is
+// destroyed in the database.
+type AfterDestroyable interface {
+	AfterDestroy(*Connection) error
+}
+
+// AfterFindable callback will be called after a record, or records,
+// has been retrieved from the database.
+type AfterFindable interface {
+	AfterFind(*Connection) error
+}
+
+func (m *Model) beforeSave(c *Connection) error {
+	if x, ok := m.Value.(BeforeSaveable); ok {
+		return x.BeforeSave(c)
+	}
+	return nil
+}
+
+func (m *Model) afterSave(c *Connection) error {
+	if x, ok := m.Value.(AfterSaveable); ok {
+		return x.AfterSave(c)
+	}
+	return nil
+}
+
+func (m *Model) beforeCreate(c *Connection) error {
+	if x, ok := m.Value.(BeforeCreateable); ok {
+		return x.BeforeCreate(c)
+	}
+	return nil
+}
+
+func (m *Model) afterCreate(c *Connection) error {
+	if x, ok := m.Value.(AfterCreateable); ok {
+		return x.AfterCreate(c)
+	}
+	return nil
+}
+
+func (m *Model) beforeUpdate(c *Connection) error {
+	if x, ok := m.Value.(BeforeUpdateable); ok {
+		return x.BeforeUpdate(c)
+	}
+	return nil
+}
+
+func (m *Model) afterUpdate(c *Connection) error {
+	if x, ok := m.Value.(AfterUpdateable); ok {
+		return x.AfterUpdate(c)
+	}
+	return nil
+}
+
+func (m *Model) beforeDestroy(c *Connection) error {
+	if x, ok := m.Value.(BeforeDestroyable); ok {
+		return x.BeforeDestroy(c)
+	}
+	return nil
+}
+
+func (m *Model) afterDestroy(c *Connection) error {
+	if x, ok := m.Value.(AfterDestroyable); ok {
+		return x.AfterDestroy(c)
+	}
+	return nil
+}
+
+func (m *Model) afterFind(c *Connection) error {
+	if x, ok := m.Value.(AfterFindable); ok {
+		return x.AfterFind(c)
+	}
+	return nil
+}
+
+// beforeCreateHooks runs the hooks that precede an insert
+func (m *Model) beforeCreateHooks(c *Connection) error {
+	if err := m.beforeSave(c); err != nil {
+		return err
+	}
+	return m.beforeCreate(c)
+}
+
+// afterCreateHooks runs the hooks that follow an insert
+func (m *Model) afterCreateHooks(c *Connection) error {
+	if err := m.afterCreate(c); err != nil {
+		return err
+	}
+	return m.afterSave(c)
+}
+
+// beforeUpdateHooks runs the hooks that precede an update
+func (m *Model) beforeUpdateHooks(c *Connection) error {
+	if err := m.beforeSave(c); err != nil {
+		return err
+	}
+	return m.beforeUpdate(c)
+}
+
+// afterUpdateHooks runs the hooks that follow an update
+func (m *Model) afterUpdateHooks(c *Connection) error {
+	if err := m.afterUpdate(c); err != nil {
+		return err
+	}
+	return m.afterSave(c)
+}
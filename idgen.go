@@ -0,0 +1,178 @@
+package goala
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// IDGenerator creates the primary key of a new record. NewID receives the
+// type of the `ID` field and returns a value of, or convertible to, that
+// type. A nil id leaves the field untouched.
+type IDGenerator interface {
+	NewID(t reflect.Type) (interface{}, error)
+}
+
+// IDGeneratorFunc adapts a function to the `IDGenerator` interface.
+type IDGeneratorFunc func(t reflect.Type) (interface{}, error)
+
+// NewID calls f(t).
+func (f IDGeneratorFunc) NewID(t reflect.Type) (interface{}, error) {
+	return f(t)
+}
+
+// IDGeneratorAble lets a model choose the generator of its ids over the one
+// of the `Connection`.
+type IDGeneratorAble interface {
+	IDGenerator() IDGenerator
+}
+
+var (
+	uuidType   = reflect.TypeOf(uuid.UUID{})
+	stringType = reflect.TypeOf("")
+)
+
+// UUIDv4 generates random UUIDs for `uuid.UUID` and string ids. It is the
+// default generator.
+var UUIDv4 IDGenerator = IDGeneratorFunc(func(t reflect.Type) (interface{}, error) {
+	if t != uuidType && t.Kind() != reflect.String {
+		return nil, nil
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	return id, nil
+})
+
+// UUIDv7 generates time ordered UUIDs for `uuid.UUID` and string ids, which
+// keeps inserts into the primary key index append-only.
+var UUIDv7 IDGenerator = IDGeneratorFunc(func(t reflect.Type) (interface{}, error) {
+	if t != uuidType && t.Kind() != reflect.String {
+		return nil, nil
+	}
+	id := uuid.UUID{}
+	if _, err := rand.Read(id[6:]); err != nil {
+		return nil, err
+	}
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[:6], ts[2:])
+	id[6] = id[6]&0x0f | 0x70
+	id[8] = id[8]&0x3f | 0x80
+	return id, nil
+})
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID generates time ordered ULIDs. String ids hold the 26 character
+// encoding and `uuid.UUID` ids its 16 bytes.
+var ULID IDGenerator = IDGeneratorFunc(func(t reflect.Type) (interface{}, error) {
+	if t != uuidType && t.Kind() != reflect.String {
+		return nil, nil
+	}
+	var id [16]byte
+	if _, err := rand.Read(id[6:]); err != nil {
+		return nil, err
+	}
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[:6], ts[2:])
+	if t == uuidType {
+		return uuid.UUID(id), nil
+	}
+	return encodeULID(id), nil
+})
+
+// encodeULID encodes 128 bits as 26 characters of Crockford's base32
+func encodeULID(id [16]byte) string {
+	var sb strings.Builder
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	// the first character holds the top 3 bits, the other 25 five bits each
+	for i := 0; i < 26; i++ {
+		shift := uint(125 - 5*i)
+		var v uint64
+		switch {
+		case shift >= 64:
+			v = hi >> (shift - 64)
+		case shift+5 > 64:
+			v = hi<<(64-shift) | lo>>shift
+		default:
+			v = lo >> shift
+		}
+		sb.WriteByte(crockford[v&0x1f])
+	}
+	return sb.String()
+}
+
+// CallerProvided never generates ids. The id of each record must be set
+// before it is created.
+var CallerProvided IDGenerator = IDGeneratorFunc(func(t reflect.Type) (interface{}, error) {
+	return nil, nil
+})
+
+// snowflakeEpoch is the epoch of the Twitter snowflake format
+const snowflakeEpoch = 1288834974657
+
+// Snowflake generates time ordered int64 ids made of a 41 bit millisecond
+// timestamp, a 10 bit node and a 12 bit sequence.
+type Snowflake struct {
+	node     int64
+	mu       sync.Mutex
+	lastMS   int64
+	sequence int64
+}
+
+// NewSnowflake returns a snowflake generator for a node between 0 and 1023.
+func NewSnowflake(node int64) (*Snowflake, error) {
+	if node < 0 || node > 1023 {
+		return nil, errors.Errorf("snowflake node must be within 0:1023: %d", node)
+	}
+	return &Snowflake{node: node}, nil
+}
+
+// NewID implements the `IDGenerator` interface for integer ids.
+func (s *Snowflake) NewID(t reflect.Type) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Uint64:
+	default:
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ms := time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+	if ms < s.lastMS {
+		ms = s.lastMS
+	}
+	if ms == s.lastMS {
+		s.sequence = (s.sequence + 1) & 0xfff
+		if s.sequence == 0 {
+			ms++
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastMS = ms
+	return ms<<22 | s.node<<12 | s.sequence, nil
+}
+
+// idGenerator returns the generator of the model, of the connection or the
+// default one in that order
+func (c *Connection) idGenerator(m *Model) IDGenerator {
+	if g, ok := m.Value.(IDGeneratorAble); ok {
+		return g.IDGenerator()
+	}
+	if c.IDGenerator != nil {
+		return c.IDGenerator
+	}
+	return UUIDv4
+}
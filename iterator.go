@@ -0,0 +1,157 @@
+package goala
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// WithContext returns a query whose reads, First, All, Each and Iter, are
+// bound to ctx. Cancelling ctx stops an iteration. Writes such as
+// UpdateAll, Delete and Create do not use ctx.
+//
+//	c.WithContext(ctx).Where("active = ?", true).Each(u, fn)
+func (c *Connection) WithContext(ctx context.Context) *Query {
+	return Q(c).WithContext(ctx)
+}
+
+// WithContext returns a query whose reads, First, All, Each and Iter, are
+// bound to ctx. Cancelling ctx stops an iteration. Writes such as
+// UpdateAll, Delete and Create do not use ctx.
+//
+//	q.WithContext(ctx).All(&[]User{})
+func (q *Query) WithContext(ctx context.Context) *Query {
+	q.ctx = ctx
+	return q
+}
+
+// context returns the context of the query
+func (q Query) context() context.Context {
+	if q.ctx == nil {
+		return context.Background()
+	}
+	return q.ctx
+}
+
+// Iterator streams the rows of a query. It must be closed, which Next does
+// once the rows are exhausted.
+//
+//	it, err := q.Iter(&User{})
+//	defer it.Close()
+//	for it.Next() {
+//		u := &User{}
+//		if err := it.Scan(u); err != nil {
+//			return err
+//		}
+//	}
+//	return it.Err()
+type Iterator struct {
+	rows *sqlx.Rows
+	conn *Connection
+	ctx  context.Context
+	err  error
+}
+
+// Iter starts streaming the rows of the query. model is a pointer to the
+// struct the rows are scanned into and sets the table and columns.
+func (c *Connection) Iter(model interface{}) (*Iterator, error) {
+	return Q(c).Iter(model)
+}
+
+// Iter starts streaming the rows of the query. model is a pointer to the
+// struct the rows are scanned into and sets the table and columns.
+func (q *Query) Iter(model interface{}) (*Iterator, error) {
+	m := q.Connection.newModel(model)
+	query, err := q.resolveScopes(m)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.Connection.Dialect.SelectRows(q.Connection.db(), m, query)
+	if err != nil {
+		return nil, err
+	}
+	return &Iterator{rows: rows, conn: q.Connection, ctx: query.context()}, nil
+}
+
+// Next prepares the next row for Scan. It returns false and closes the
+// rows when they are exhausted, on error or when the context is done.
+func (it *Iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		it.Close()
+		return false
+	}
+	if !it.rows.Next() {
+		it.err = it.rows.Err()
+		it.Close()
+		return false
+	}
+	return true
+}
+
+// Scan copies the current row into model and runs its AfterFind hook
+func (it *Iterator) Scan(model interface{}) error {
+	m := it.conn.newModel(model)
+	if err := it.conn.Dialect.ScanRow(it.rows, m); err != nil {
+		return errors.Wrap(err, "scanning row")
+	}
+	return m.afterFind(it.conn)
+}
+
+// Columns returns the column names of the rows
+func (it *Iterator) Columns() ([]string, error) {
+	return it.rows.Columns()
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close closes the rows. It is safe to call more than once.
+func (it *Iterator) Close() error {
+	return it.rows.Close()
+}
+
+// Each scans every row of the query into model, which is reset to its zero
+// value first, and calls fn. An error from fn stops the iteration and is
+// returned. Rows are streamed rather than loaded into memory.
+//
+//	u := &User{}
+//	err := c.Where("active = ?", true).Each(u, func() error {
+//		return enc.Encode(u)
+//	})
+func (c *Connection) Each(model interface{}, fn func() error) error {
+	return Q(c).Each(model, fn)
+}
+
+// Each scans every row of the query into model, which is reset to its zero
+// value first, and calls fn. An error from fn stops the iteration and is
+// returned. Rows are streamed rather than loaded into memory.
+func (q *Query) Each(model interface{}, fn func() error) error {
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("must pass pointer to struct")
+	}
+	it, err := q.Iter(model)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	zero := reflect.Zero(v.Elem().Type())
+	for it.Next() {
+		v.Elem().Set(zero)
+		if err := it.Scan(model); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
@@ -0,0 +1,38 @@
+//go:build go1.23
+
+package goala
+
+import "iter"
+
+// Rows returns the rows of a query as a sequence of new values of T. The
+// rows are closed when the loop ends. An error is yielded once with a nil
+// value and ends the sequence.
+//
+//	for u, err := range goala.Rows[User](c.Where("active = ?", true)) {
+//		if err != nil {
+//			return err
+//		}
+//	}
+func Rows[T any](q *Query) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		it, err := q.Iter(new(T))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer it.Close()
+		for it.Next() {
+			v := new(T)
+			if err := it.Scan(v); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+//go:build go1.23
+
+package goala
+
+import "testing"
+
+func TestRowsSeq(t *testing.T) {
+	db := newIteratorConnection(t)
+	defer db.Close()
+	ages := 0
+	for row, err := range Rows[csvStruct](db.Where("age > ?", 1)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ages += row.Age
+	}
+	if want, have := 5, ages; want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	for range Rows[csvStruct](Q(db)) {
+		break
+	}
+	if err := db.First(&csvStruct{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package goala
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func newIteratorConnection(t *testing.T) *Connection {
+	db := newTestConnection(t)
+	if err := db.CreateTable(&csvStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateMany(&[]csvStruct{{Name: "a", Age: 1}, {Name: "b", Age: 2}, {Name: "c", Age: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestEach(t *testing.T) {
+	db := newIteratorConnection(t)
+	defer db.Close()
+	row := &csvStruct{}
+	names := ""
+	if err := db.Order("age").Each(row, func() error {
+		names += row.Name
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := "abc", names; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	stop := errors.New("stop")
+	if err := Q(db).Each(row, func() error { return stop }); err != stop {
+		t.Errorf("want: %v have: %v", stop, err)
+	}
+	// the single connection is free again once every iteration has closed
+	// its rows
+	if err := db.First(&csvStruct{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIterContext(t *testing.T) {
+	db := newIteratorConnection(t)
+	defer db.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	it, err := db.WithContext(ctx).Iter(&csvStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	if !it.Next() {
+		t.Fatalf("want a row, have: %v", it.Err())
+	}
+	cancel()
+	if it.Next() {
+		t.Error("want iteration to stop once the context is cancelled")
+	}
+	if want, have := context.Canceled, errors.Cause(it.Err()); want != have {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	q := db.WithContext(ctx)
+	if want, have := context.Canceled, errors.Cause(q.First(&csvStruct{})); want != have {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	if want, have := context.Canceled, errors.Cause(q.All(&[]csvStruct{})); want != have {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+}
@@ -0,0 +1,104 @@
+package goala
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// JSON stores a value of any type as a JSON document. It maps to TEXT on
+// sqlite3 and JSONB on postgres.
+//
+//	Meta goala.JSON[map[string]string] `db:"meta"`
+type JSON[T any] struct {
+	Data T
+}
+
+// NewJSON returns a JSON column holding v
+func NewJSON[T any](v T) JSON[T] {
+	return JSON[T]{Data: v}
+}
+
+// ColumnType implements the ColumnTyper interface
+func (JSON[T]) ColumnType() ColumnType {
+	return ColumnType{DataType: JSONType}
+}
+
+// Scan implements the sql.Scanner interface. NULL leaves the zero value of
+// T.
+func (j *JSON[T]) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		var zero T
+		j.Data = zero
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.Errorf("cannot scan %T into JSON", value)
+	}
+	return errors.Wrap(json.Unmarshal(data, &j.Data), "scanning JSON")
+}
+
+// Value implements the driver.Valuer interface
+func (j JSON[T]) Value() (driver.Value, error) {
+	data, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling JSON")
+	}
+	return string(data), nil
+}
+
+// MarshalJSON encodes the underlying value
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+// UnmarshalJSON decodes into the underlying value
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.Data)
+}
+
+var jsonOperators = []string{"=", "!=", "<>", "<", "<=", ">", ">=", "LIKE", "NOT LIKE", "IS", "IS NOT"}
+
+type jsonCondition struct {
+	column string
+	path   string
+	op     string
+	value  interface{}
+}
+
+func (c jsonCondition) toClause(d dialect) clause {
+	return clause{
+		fmt.Sprintf("%s %s ?", d.JSONExtract(d.Quote(c.column)), c.op),
+		[]interface{}{c.path, c.value},
+	}
+}
+
+// WhereJSON will append a where clause comparing the value at a JSON path of
+// a column.
+//
+//	c.WhereJSON("meta", "$.tier", "=", "gold")
+func (c *Connection) WhereJSON(column, path, op string, value interface{}) *Query {
+	return Q(c).WhereJSON(column, path, op, value)
+}
+
+// WhereJSON will append a where clause comparing the value at a JSON path of
+// a column. The path is passed as an argument and the operator must be a
+// comparison, any other operator fails the query when it is executed.
+//
+//	q.WhereJSON("meta", "$.tier", "=", "gold")
+func (q *Query) WhereJSON(column, path, op string, value interface{}) *Query {
+	op = strings.ToUpper(strings.TrimSpace(op))
+	if !isin(jsonOperators, op) {
+		q.addError(errors.Errorf("where json: unsupported operator: %s", op))
+		return q
+	}
+	return q.WhereCond(jsonCondition{column, path, op, value})
+}
@@ -0,0 +1,96 @@
+package goala
+
+import (
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type jsonMeta struct {
+	Tier  string `json:"tier"`
+	Score int    `json:"score"`
+}
+
+type jsonStruct struct {
+	ID   uuid.UUID      `db:"id"`
+	Meta JSON[jsonMeta] `db:"meta"`
+}
+
+func (jsonStruct) TableName() string { return "json_struct" }
+
+func TestJSONColumn(t *testing.T) {
+	schema, err := createSchema(&jsonStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for dialect, want := range map[string]string{
+		"sqlite3":  "CREATE TABLE json_struct (id TEXT,meta TEXT)",
+		"postgres": "CREATE TABLE json_struct (id TEXT,meta JSONB)",
+	} {
+		have, err := schema.SQLFor(dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want != have {
+			t.Errorf("want: %s have: %s", want, have)
+		}
+	}
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&jsonStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&jsonStruct{Meta: NewJSON(jsonMeta{"gold", 3})}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&jsonStruct{Meta: NewJSON(jsonMeta{"silver", 1})}); err != nil {
+		t.Fatal(err)
+	}
+	q := db.WhereJSON("meta", "$.tier", "=", "gold")
+	sql, args := mustSQL(t, q, db.newModel(&jsonStruct{}))
+	if want, have := `SELECT id,meta FROM json_struct WHERE json_extract("meta", ?) = ?`, sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if len(args) != 2 || args[0] != "$.tier" || args[1] != "gold" {
+		t.Errorf("unexpected args: %v", args)
+	}
+	found := []jsonStruct{}
+	if err := q.All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Meta.Data.Score != 3 {
+		t.Errorf("unexpected records: %+v", found)
+	}
+	bad := db.From(&jsonStruct{}).WhereJSON("meta", "$.tier", "; DROP", "x")
+	if _, err := bad.Delete(&jsonStruct{}); err == nil {
+		t.Error("expected error for unsupported operator")
+	}
+	if _, err := bad.UpdateAll(map[string]interface{}{"meta": "{}"}); err == nil {
+		t.Error("expected error for unsupported operator")
+	}
+	if err := db.All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 2, len(found); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func TestJSONColumnCreateMany(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&jsonStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	want := jsonMeta{"it's silver", 1}
+	if err := db.CreateMany(&[]jsonStruct{{Meta: NewJSON(want)}}); err != nil {
+		t.Fatal(err)
+	}
+	found := &jsonStruct{}
+	if err := db.First(found); err != nil {
+		t.Fatal(err)
+	}
+	if have := found.Meta.Data; want != have {
+		t.Errorf("want: %+v have: %+v", want, have)
+	}
+}
@@ -0,0 +1,345 @@
+package goala
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// columnField is a struct field that maps to a column. Index is the path
+// to the field through embedded structs.
+type columnField struct {
+	Name  string
+	Field reflect.StructField
+	Index []int
+}
+
+// Type returns the type of the field with pointers removed
+func (c columnField) Type() reflect.Type {
+	t := c.Field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// Nullable reports whether the field is a pointer and can hold NULL
+func (c columnField) Nullable() bool {
+	return c.Field.Type.Kind() == reflect.Ptr
+}
+
+// columnName returns the column of a struct field. The `db` tag wins over
+// the naming strategy and `db:"-"` opts out.
+func columnName(f reflect.StructField, ns NamingStrategy) (string, bool) {
+	tag := f.Tag.Get("db")
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+	switch {
+	case tag == "-":
+		return "", false
+	case tag != "":
+		return tag, true
+	case ns == nil || f.PkgPath != "" || f.Anonymous:
+		return "", false
+	}
+	return ns.ColumnName(f.Name), true
+}
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// isEmbeddedStruct reports whether an anonymous field is a struct whose
+// fields should be flattened into the columns of its parent. Structs that
+// are values of their own, like `time.Time` or the nulls types, are not.
+func isEmbeddedStruct(f reflect.StructField) bool {
+	if !f.Anonymous || f.Tag.Get("db") != "" {
+		return false
+	}
+	t := f.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	pt := reflect.PtrTo(t)
+	return !t.Implements(valuerType) && !pt.Implements(valuerType) && !pt.Implements(scannerType)
+}
+
+// structMetadata is the reflection metadata of a struct type computed once
+// and shared by Model, Schema and the helpers
+type structMetadata struct {
+	Type       reflect.Type
+	Fields     []columnField
+	Columns    []string
+	DataTypes  []int
+	SQLTypes   []string
+	PrimaryKey *columnField
+	Timestamps map[string]columnField
+	Version    *columnField
+
+	columns       string
+	tokenized     string
+	updateColumns []string
+	unknownType   reflect.Type
+	byColumn      map[string]int
+
+	projectionsMutex sync.RWMutex
+	projections      map[string][]string
+}
+
+type metadataKey struct {
+	t  reflect.Type
+	ns NamingStrategy
+}
+
+var metadataCache = map[metadataKey]*structMetadata{}
+var metadataCacheMutex = sync.RWMutex{}
+
+// metadataFor returns the metadata of a struct type, a pointer to one or a
+// slice of either. Results are cached per type and naming strategy.
+func metadataFor(t reflect.Type, ns NamingStrategy) *structMetadata {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	cacheable := ns == nil || reflect.TypeOf(ns).Comparable()
+	key := metadataKey{t, ns}
+	if cacheable {
+		metadataCacheMutex.RLock()
+		meta, ok := metadataCache[key]
+		metadataCacheMutex.RUnlock()
+		if ok {
+			return meta
+		}
+	}
+	meta := newStructMetadata(t, ns)
+	if cacheable {
+		metadataCacheMutex.Lock()
+		if cached, ok := metadataCache[key]; ok {
+			meta = cached
+		} else {
+			metadataCache[key] = meta
+		}
+		metadataCacheMutex.Unlock()
+	}
+	return meta
+}
+
+func newStructMetadata(t reflect.Type, ns NamingStrategy) *structMetadata {
+	meta := &structMetadata{
+		Type:        t,
+		Fields:      []columnField{},
+		Timestamps:  map[string]columnField{},
+		byColumn:    map[string]int{},
+		projections: map[string][]string{},
+	}
+	if t.Kind() != reflect.Struct {
+		return meta
+	}
+	meta.Fields = walkFields(t, ns, nil)
+	meta.Columns = make([]string, len(meta.Fields))
+	meta.DataTypes = make([]int, len(meta.Fields))
+	meta.SQLTypes = make([]string, len(meta.Fields))
+	tokens := make([]string, len(meta.Fields))
+	for i, col := range meta.Fields {
+		meta.Columns[i] = col.Name
+		meta.byColumn[col.Name] = i
+		tokens[i] = ":" + col.Name
+		dType, ok := lookupDataType(col)
+		if !ok && meta.unknownType == nil {
+			meta.unknownType = col.Field.Type
+		}
+		meta.DataTypes[i] = dType
+		meta.SQLTypes[i] = columnSQLType(col.Type())
+	}
+	meta.columns = strings.Join(meta.Columns, ",")
+	meta.tokenized = strings.Join(tokens, ", ")
+	for i := range meta.Fields {
+		col := &meta.Fields[i]
+		if meta.PrimaryKey == nil && (col.Name == "id" || col.Field.Name == "ID") {
+			meta.PrimaryKey = col
+		}
+		if kind := col.Field.Tag.Get("timestamp"); kind != "" {
+			if _, ok := meta.Timestamps[kind]; !ok {
+				meta.Timestamps[kind] = *col
+			}
+		}
+	}
+	for kind, name := range map[string]string{
+		createdTimestamp: "CreatedAt",
+		updatedTimestamp: "UpdatedAt",
+		deletedTimestamp: "DeletedAt",
+	} {
+		if _, ok := meta.Timestamps[kind]; ok {
+			continue
+		}
+		if i, ok := meta.fieldIndex(name); ok {
+			meta.Timestamps[kind] = meta.Fields[i]
+		}
+	}
+	for _, name := range []string{"Version", "LockVersion"} {
+		if i, ok := meta.fieldIndex(name); ok && isIntKind(meta.Fields[i].Field.Type.Kind()) {
+			meta.Version = &meta.Fields[i]
+			break
+		}
+	}
+	for _, name := range []string{"version", "lock_version"} {
+		if i, ok := meta.byColumn[name]; ok && meta.Version == nil && isIntKind(meta.Fields[i].Field.Type.Kind()) {
+			meta.Version = &meta.Fields[i]
+			break
+		}
+	}
+	primaryKey, createdAt := "id", ""
+	if meta.PrimaryKey != nil {
+		primaryKey = meta.PrimaryKey.Name
+	}
+	if f, ok := meta.Timestamps[createdTimestamp]; ok {
+		createdAt = f.Name
+	}
+	meta.updateColumns = []string{}
+	for _, col := range meta.Columns {
+		switch col {
+		case primaryKey, "created_at", createdAt:
+			continue
+		default:
+			meta.updateColumns = append(meta.updateColumns, col)
+		}
+	}
+	return meta
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int16, reflect.Int8, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// fieldIndex returns the position of the column field with the given Go
+// field name
+func (meta *structMetadata) fieldIndex(name string) (int, bool) {
+	for i, col := range meta.Fields {
+		if col.Field.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// column returns the column field with the given column name
+func (meta *structMetadata) column(name string) (columnField, bool) {
+	i, ok := meta.byColumn[name]
+	if !ok {
+		return columnField{}, false
+	}
+	return meta.Fields[i], true
+}
+
+// dataTypes returns the data types of the columns and panics when a field
+// has a type that does not map to one
+func (meta *structMetadata) dataTypes() []int {
+	if meta.unknownType != nil {
+		panic(fmt.Sprintf("unknown field type: %v", meta.unknownType))
+	}
+	return meta.DataTypes
+}
+
+// maxProjections bounds the number of projections cached per struct type
+const maxProjections = 64
+
+// projection returns the columns of a select with the given selected and
+// excluded columns. Up to maxProjections projections are cached on the
+// metadata, later ones are computed on every call.
+func (meta *structMetadata) projection(selected, excluded []string) []string {
+	if len(selected) == 0 && len(excluded) == 0 {
+		return meta.Columns
+	}
+	key := strings.Join(selected, ",") + "|" + strings.Join(excluded, ",")
+	meta.projectionsMutex.RLock()
+	cols, ok := meta.projections[key]
+	meta.projectionsMutex.RUnlock()
+	if ok {
+		return cols
+	}
+	if len(selected) == 0 {
+		selected = meta.Columns
+	}
+	cols = make([]string, 0, len(selected))
+	for _, col := range selected {
+		if isin(excluded, col) {
+			continue
+		}
+		cols = append(cols, col)
+	}
+	meta.projectionsMutex.Lock()
+	if len(meta.projections) < maxProjections {
+		meta.projections[key] = cols
+	}
+	meta.projectionsMutex.Unlock()
+	return cols
+}
+
+// columnFields returns the fields of a struct type that map to columns.
+// Embedded structs are flattened.
+func columnFields(t reflect.Type, ns NamingStrategy) []columnField {
+	return metadataFor(t, ns).Fields
+}
+
+func walkFields(t reflect.Type, ns NamingStrategy, index []int) []columnField {
+	cols := []columnField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		if isEmbeddedStruct(f) {
+			et := f.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			cols = append(cols, walkFields(et, ns, fieldIndex)...)
+			continue
+		}
+		if name, ok := columnName(f, ns); ok {
+			cols = append(cols, columnField{name, f, fieldIndex})
+		}
+	}
+	return cols
+}
+
+// fieldValue returns the value of a column field of a struct. ok is false
+// when the field sits behind a nil embedded pointer.
+func fieldValue(v reflect.Value, col columnField) (reflect.Value, bool) {
+	for i, x := range col.Index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldValueAlloc returns the value of a column field of a struct,
+// allocating nil embedded pointers on the way
+func fieldValueAlloc(v reflect.Value, col columnField) reflect.Value {
+	for i, x := range col.Index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,154 @@
+package goala
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/estenssoros/goala/nulls"
+	uuid "github.com/satori/go.uuid"
+)
+
+type metadataStruct struct {
+	ID        uuid.UUID    `db:"id"`
+	Name      string       `db:"name"`
+	Email     nulls.String `db:"email"`
+	Version   int          `db:"version"`
+	Inserted  nulls.Time   `db:"inserted" timestamp:"created"`
+	UpdatedAt nulls.Time   `db:"updated_at"`
+}
+
+func (metadataStruct) TableName() string { return "shared" }
+
+type otherMetadataStruct struct {
+	ID   uuid.UUID `db:"id"`
+	Blob string    `db:"blob"`
+}
+
+func (otherMetadataStruct) TableName() string { return "shared" }
+
+func TestStructMetadata(t *testing.T) {
+	meta := metadataFor(reflect.TypeOf(&[]metadataStruct{}), nil)
+	if meta != metadataFor(reflect.TypeOf(metadataStruct{}), nil) {
+		t.Error("want metadata to be cached per struct type")
+	}
+	if want, have := "id,name,email,version,inserted,updated_at", meta.columns; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if meta.PrimaryKey == nil || meta.PrimaryKey.Name != "id" {
+		t.Errorf("want primary key id, have: %v", meta.PrimaryKey)
+	}
+	if meta.Version == nil || meta.Version.Name != "version" {
+		t.Errorf("want version column, have: %v", meta.Version)
+	}
+	if want, have := "inserted", meta.Timestamps[createdTimestamp].Name; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := []string{"name", "email", "version", "updated_at"}, meta.updateColumns; !reflect.DeepEqual(want, have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+}
+
+func TestVersionColumn(t *testing.T) {
+	meta := metadataFor(reflect.TypeOf(struct {
+		Revision int `db:"lock_version"`
+	}{}), nil)
+	if meta.Version == nil || meta.Version.Name != "lock_version" {
+		t.Errorf("want version column lock_version, have: %v", meta.Version)
+	}
+}
+
+func TestColumnsSharedTableName(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	for _, tc := range []struct {
+		model interface{}
+		want  string
+	}{
+		{&metadataStruct{}, "SELECT id,name,email,version,inserted,updated_at FROM shared"},
+		{&otherMetadataStruct{}, "SELECT id,blob FROM shared"},
+	} {
+		sql, _ := mustSQL(t, Q(db), db.newModel(tc.model))
+		if sql != tc.want {
+			t.Errorf("want: %s have: %s", tc.want, sql)
+		}
+	}
+}
+
+type keyedStruct struct {
+	Key  int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func (keyedStruct) TableName() string { return "keyed" }
+
+func TestPrimaryKey(t *testing.T) {
+	m := &Model{Value: &keyedStruct{Key: 7}}
+	if want, have := int64(7), m.ID(); want != have {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	where, args, err := m.whereID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := "id = ?", where; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := []interface{}{int64(7)}, args; !reflect.DeepEqual(want, have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	if want, have := []string{"name"}, m.updateColumnSlice(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	if _, _, err := (&Model{Value: &struct{ Name string }{}}).whereID(); err == nil {
+		t.Error("expected error for model without primary key")
+	}
+}
+
+func TestProjectionCacheBounded(t *testing.T) {
+	meta := newStructMetadata(reflect.TypeOf(metadataStruct{}), nil)
+	for i := 0; i < 2*maxProjections; i++ {
+		cols := meta.projection([]string{"id", "name", strings.Repeat("x", i)}, nil)
+		if want, have := 3, len(cols); want != have {
+			t.Fatalf("want: %d have: %d", want, have)
+		}
+	}
+	if want, have := maxProjections, len(meta.projections); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func BenchmarkColumnSlice(b *testing.B) {
+	m := &Model{Value: &metadataStruct{}}
+	for i := 0; i < b.N; i++ {
+		m.ColumnSlice()
+	}
+}
+
+func BenchmarkColumnSliceUncached(b *testing.B) {
+	t := reflect.TypeOf(metadataStruct{})
+	for i := 0; i < b.N; i++ {
+		newStructMetadata(t, nil)
+	}
+}
+
+func BenchmarkDataTypes(b *testing.B) {
+	v := &metadataStruct{}
+	for i := 0; i < b.N; i++ {
+		DataTypes(v)
+	}
+}
+
+func BenchmarkUpdateString(b *testing.B) {
+	m := &Model{Value: &metadataStruct{}}
+	for i := 0; i < b.N; i++ {
+		m.UpdateString()
+	}
+}
+
+func BenchmarkTokenizedString(b *testing.B) {
+	m := &Model{Value: &metadataStruct{}}
+	for i := 0; i < b.N; i++ {
+		m.TokenizedString()
+	}
+}
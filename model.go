@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/estenssoros/dasorm/sqlite"
+	"github.com/estenssoros/goala/nulls"
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
@@ -20,16 +21,17 @@ type modelIterable func(*Model) error
 type Model struct {
 	Value
 	tableName string
+	naming    NamingStrategy
 }
 
-// ID returns the ID of the Model. All models must have an `ID` field this is
-// of type `uuid.UUID`.
+// ID returns the primary key of the Model, which is the field with the `id`
+// column or the `ID` field. It panics when the model has neither.
 func (m *Model) ID() interface{} {
-	fbn, err := m.fieldByName("ID")
+	_, id, err := m.primaryKey()
 	if err != nil {
 		panic(err)
 	}
-	return fbn.Interface()
+	return id
 }
 
 // TableNameAble interface allows for the customize table mapping
@@ -76,12 +78,21 @@ func (m *Model) typeName(t reflect.Type) string {
 			name := out[0].String()
 			return name
 		}
-		return el.Name()
+		return m.strategyTableName(el.Name())
 	default:
-		return t.Name()
+		return m.strategyTableName(t.Name())
 	}
 }
 
+// strategyTableName applies the naming strategy of the model to the name of
+// its Go type
+func (m *Model) strategyTableName(name string) string {
+	if ns := m.namingStrategy(); ns != nil {
+		return ns.TableName(name)
+	}
+	return name
+}
+
 // SQLViewAble returns the sql associated with a view for a particular struct
 type SQLViewAble interface {
 	SQLView() string
@@ -128,42 +139,180 @@ func (m *Model) fieldByName(s string) (reflect.Value, error) {
 	return fbn, nil
 }
 
-func (m *Model) setID(i interface{}) {
-	fbn, err := m.fieldByName("ID")
-	if err == nil {
-		if fbn.Interface().(uuid.UUID) == (uuid.UUID{}) {
-			fbn.Set(reflect.ValueOf(i))
+// setID generates an id for the model when its primary key is empty. The
+// generated value is converted to the type of the field.
+func (m *Model) setID(gen IDGenerator) error {
+	pk := m.metadata().PrimaryKey
+	if pk == nil {
+		return nil
+	}
+	fbn := fieldValueAlloc(reflect.Indirect(reflect.ValueOf(m.Value)), *pk)
+	if !fbn.IsZero() {
+		return nil
+	}
+	id, err := gen.NewID(fbn.Type())
+	if err != nil {
+		return errors.Wrap(err, "generating id")
+	}
+	if id == nil {
+		return nil
+	}
+	v := reflect.ValueOf(id)
+	switch {
+	case v.Type().AssignableTo(fbn.Type()):
+		fbn.Set(v)
+	case v.Type() == uuidType && fbn.Kind() == reflect.String:
+		fbn.SetString(id.(uuid.UUID).String())
+	case v.Kind() == reflect.String && fbn.Type() == uuidType:
+		u, err := uuid.FromString(v.String())
+		if err != nil {
+			return errors.Wrap(err, "generating id")
 		}
+		fbn.Set(reflect.ValueOf(u))
+	case v.Type().ConvertibleTo(fbn.Type()):
+		fbn.Set(v.Convert(fbn.Type()))
+	default:
+		return errors.Errorf("generated id of type %s does not fit field of type %s", v.Type(), fbn.Type())
 	}
+	return nil
 }
 
-func (m *Model) touchCreatedAt() {
-	fbn, err := m.fieldByName("CreatedAt")
-	if err == nil {
-		switch fbn.Type() {
-		case reflect.TypeOf(time.Time{}):
-			fbn.Set(reflect.ValueOf(time.Now().UTC()))
-		case reflect.TypeOf(sqlite.Time{}):
-			fbn.Set(reflect.ValueOf(sqlite.Time(time.Now().UTC())))
-		}
+const (
+	createdTimestamp = "created"
+	updatedTimestamp = "updated"
+	deletedTimestamp = "deleted"
+)
+
+// metadata returns the cached reflection metadata of the model type
+func (m *Model) metadata() *structMetadata {
+	return metadataFor(reflect.TypeOf(m.Value), m.namingStrategy())
+}
+
+// timestampField returns the column field of the model that holds the given
+// kind of timestamp. Fields are declared with a `timestamp` tag and default
+// to `CreatedAt`, `UpdatedAt` and `DeletedAt`.
+//
+//	Inserted time.Time `db:"inserted" timestamp:"created"`
+func (m *Model) timestampField(kind string) (columnField, bool) {
+	f, ok := m.metadata().Timestamps[kind]
+	return f, ok
+}
+
+// timestampColumn returns the db column of the given kind of timestamp
+func (m *Model) timestampColumn(kind string) (string, bool) {
+	f, ok := m.timestampField(kind)
+	if !ok {
+		return "", false
 	}
+	return f.Name, true
 }
 
-func (m *Model) touchUpdatedAt() {
-	fbn, err := m.fieldByName("UpdatedAt")
-	if err == nil {
-		switch fbn.Type() {
-		case reflect.TypeOf(time.Time{}):
-			fbn.Set(reflect.ValueOf(time.Now().UTC()))
-		case reflect.TypeOf(sqlite.Time{}):
-			fbn.Set(reflect.ValueOf(sqlite.Time(time.Now().UTC())))
-		}
+func (m *Model) touchTimestamp(kind string, now time.Time) {
+	f, ok := m.timestampField(kind)
+	if !ok {
+		return
+	}
+	fbn := fieldValueAlloc(reflect.Indirect(reflect.ValueOf(m.Value)), f)
+	switch fbn.Type() {
+	case reflect.TypeOf(time.Time{}):
+		fbn.Set(reflect.ValueOf(now))
+	case reflect.TypeOf(sqlite.Time{}):
+		fbn.Set(reflect.ValueOf(sqlite.Time(now)))
+	case reflect.TypeOf(nulls.Time{}):
+		fbn.Set(reflect.ValueOf(nulls.NewTime(now)))
+	}
+}
+
+func (m *Model) touchCreatedAt(now time.Time) {
+	m.touchTimestamp(createdTimestamp, now)
+}
+
+func (m *Model) touchUpdatedAt(now time.Time) {
+	m.touchTimestamp(updatedTimestamp, now)
+}
+
+// deletedAtColumn returns the column of the deleted timestamp of the model.
+// Only models whose deleted timestamp is a `nulls.Time` tagged with `db`
+// are soft deleted.
+func (m *Model) deletedAtColumn() (string, bool) {
+	f, ok := m.timestampField(deletedTimestamp)
+	if !ok || f.Field.Type != reflect.TypeOf(nulls.Time{}) {
+		return "", false
+	}
+	return m.timestampColumn(deletedTimestamp)
+}
+
+func (m *Model) isSoftDeletable() bool {
+	_, ok := m.deletedAtColumn()
+	return ok
+}
+
+func (m *Model) touchDeletedAt(now time.Time) {
+	m.touchTimestamp(deletedTimestamp, now)
+}
+
+// versionColumn returns the column of the `Version` or `LockVersion` field of
+// the model, or else of an integer `version` or `lock_version` column.
+// Updates of models with a version column only succeed when the version
+// matches the one in the database.
+func (m *Model) versionColumn() (string, bool) {
+	if v := m.metadata().Version; v != nil {
+		return v.Name, true
+	}
+	return "", false
+}
+
+func (m *Model) incrementVersion() {
+	if v := m.metadata().Version; v != nil {
+		fbn := fieldValueAlloc(reflect.Indirect(reflect.ValueOf(m.Value)), *v)
+		fbn.SetInt(fbn.Int() + 1)
+	}
+}
+
+// primaryKey returns the primary key column of the model and its value
+func (m *Model) primaryKey() (string, interface{}, error) {
+	pk := m.metadata().PrimaryKey
+	if pk == nil {
+		return "", nil, errors.Errorf("%s: model does not have a primary key", m.TableName())
+	}
+	v, ok := fieldValue(reflect.Indirect(reflect.ValueOf(m.Value)), *pk)
+	if !ok {
+		return pk.Name, nil, nil
+	}
+	return pk.Name, v.Interface(), nil
+}
+
+// whereID returns a condition matching the primary key of the model with
+// the id bound as an argument
+func (m *Model) whereID() (string, []interface{}, error) {
+	pk, id, err := m.primaryKey()
+	if err != nil {
+		return "", nil, err
 	}
+	return fmt.Sprintf("%s = ?", pk), []interface{}{id}, nil
 }
 
-func (m *Model) whereID() string {
-	id := m.ID()
-	return fmt.Sprintf("id ='%s'", id)
+// whereIDs returns a condition matching the primary keys of a slice of
+// models with the ids bound as arguments
+func (m *Model) whereIDs() (string, []interface{}, error) {
+	if !m.isSlice() {
+		return "", nil, errors.New("must supply slice")
+	}
+	col := ""
+	ids := []interface{}{}
+	if err := m.iterate(func(m *Model) error {
+		pk, id, err := m.primaryKey()
+		col = pk
+		ids = append(ids, id)
+		return err
+	}); err != nil {
+		return "", nil, err
+	}
+	if len(ids) == 0 {
+		return "1=0", ids, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	return fmt.Sprintf("%s IN (%s)", col, placeholders), ids, nil
 }
 
 func (m *Model) isSlice() bool {
@@ -173,32 +322,12 @@ func (m *Model) isSlice() bool {
 
 // ColumnSlice returns a slice of strings representations of db fields
 func (m *Model) ColumnSlice() []string {
-	t := reflect.TypeOf(m.Value)
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-	}
-	if t.Kind() == reflect.Slice {
-		t = t.Elem()
-		if t.Kind() == reflect.Ptr {
-			t = t.Elem()
-		}
-	}
-	numFields := t.NumField()
-	cols := []string{}
-	for i := 0; i < numFields; i++ {
-		f := t.Field(i)
-		colName := f.Tag.Get("db")
-		if colName == "" {
-			continue
-		}
-		cols = append(cols, fmt.Sprintf("%s", colName))
-	}
-	return cols
+	return append([]string{}, m.metadata().Columns...)
 }
 
 // Columns returns columns as string
 func (m *Model) Columns() string {
-	return strings.Join(m.ColumnSlice(), ",")
+	return m.metadata().columns
 }
 
 // ColumnSliceSafe returns a slice of mysql safe strings representations of db fields
@@ -230,24 +359,24 @@ func isin(list []string, test string) bool {
 
 // TokenizedString tokenizes columns
 func (m *Model) TokenizedString() string {
-	cols := m.ColumnSlice()
-	for i := 0; i < len(cols); i++ {
-		cols[i] = ":" + cols[i]
-	}
-	return strings.Join(cols, ", ")
+	return m.metadata().tokenized
 }
 
 // UpdateString returns a tokenized update string for a model
 func (m *Model) UpdateString() string {
-	cols := m.ColumnSlice()
-	out := []string{}
+	return m.updateStringFor(m.updateColumnSlice())
+}
+
+// updateColumnSlice returns the columns written by an update
+func (m *Model) updateColumnSlice() []string {
+	return append([]string{}, m.metadata().updateColumns...)
+}
+
+// updateStringFor returns a tokenized update string for the given columns
+func (m *Model) updateStringFor(cols []string) string {
+	out := make([]string, len(cols))
 	for i := 0; i < len(cols); i++ {
-		switch cols[i] {
-		case "id", "created_at":
-			continue
-		default:
-			out = append(out, fmt.Sprintf("%s = :%s", cols[i], cols[i]))
-		}
+		out[i] = fmt.Sprintf("%s = :%s", cols[i], cols[i])
 	}
 	return strings.Join(out, ", ")
 }
@@ -257,7 +386,10 @@ func (m *Model) iterate(fn modelIterable) error {
 		v := reflect.Indirect(reflect.ValueOf(m.Value))
 		for i := 0; i < v.Len(); i++ {
 			val := v.Index(i)
-			newModel := &Model{Value: val.Addr().Interface()}
+			if val.Kind() != reflect.Ptr {
+				val = val.Addr()
+			}
+			newModel := &Model{Value: val.Interface(), naming: m.naming}
 			if err := fn(newModel); err != nil {
 				return err
 			}
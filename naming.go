@@ -0,0 +1,132 @@
+package goala
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// NamingStrategy maps Go names to database names. It names the columns of
+// fields without a `db` tag and the tables of models that do not implement
+// `TableNameAble`. Fields tagged `db:"-"` are never mapped.
+type NamingStrategy interface {
+	TableName(typeName string) string
+	ColumnName(fieldName string) string
+}
+
+// DefaultNamingStrategy is used by connections without a strategy of their
+// own and by the helpers that do not take a connection, such as
+// `ColumnNames` and `InsertStmt`. When it is nil, fields without a `db` tag
+// are skipped and tables are named after their Go type. Statements of a
+// connection scan rows with the strategy in effect when they run.
+var DefaultNamingStrategy NamingStrategy
+
+// SnakeCase names tables and columns in snake_case. Tables can be given a
+// prefix and pluralised.
+//
+//	c.SetNamingStrategy(SnakeCase{TablePrefix: "app_", PluralTables: true})
+type SnakeCase struct {
+	TablePrefix  string
+	PluralTables bool
+}
+
+// TableName implements the `NamingStrategy` interface.
+func (s SnakeCase) TableName(typeName string) string {
+	name := ToSnakeCase(typeName)
+	if s.PluralTables {
+		name = pluralize(name)
+	}
+	return s.TablePrefix + name
+}
+
+// ColumnName implements the `NamingStrategy` interface.
+func (s SnakeCase) ColumnName(fieldName string) string {
+	return ToSnakeCase(fieldName)
+}
+
+// pluralize applies the regular english plural rules to a word
+func pluralize(s string) string {
+	switch {
+	case s == "":
+		return s
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "z"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+		return s[:len(s)-1] + "ies"
+	}
+	return s + "s"
+}
+
+// SetNamingStrategy sets the naming strategy of the connection. Scanning
+// rows into structs follows the same strategy.
+func (c *Connection) SetNamingStrategy(ns NamingStrategy) {
+	c.namingStrategy = ns
+	if c.DB == nil {
+		return
+	}
+	if ns == nil {
+		c.DB.Mapper = reflectx.NewMapperFunc("db", strings.ToLower)
+		return
+	}
+	c.DB.Mapper = mapperFor(ns)
+}
+
+// db returns the database of the connection with a mapper that follows the
+// naming strategy of its models, including `DefaultNamingStrategy` when the
+// connection has none of its own
+func (c *Connection) db() *sqlx.DB {
+	ns := c.namingStrategy
+	if ns == nil {
+		ns = DefaultNamingStrategy
+	}
+	if ns == nil || c.DB == nil {
+		return c.DB
+	}
+	db := *c.DB
+	db.Mapper = mapperFor(ns)
+	return &db
+}
+
+var mapperCache = map[NamingStrategy]*reflectx.Mapper{}
+var mapperCacheMutex = sync.RWMutex{}
+
+// mapperFor returns the mapper that names struct fields with a naming
+// strategy. Mappers are cached per strategy, since they cache the fields of
+// every struct they map.
+func mapperFor(ns NamingStrategy) *reflectx.Mapper {
+	if !reflect.TypeOf(ns).Comparable() {
+		return reflectx.NewMapperFunc("db", ns.ColumnName)
+	}
+	mapperCacheMutex.RLock()
+	mapper, ok := mapperCache[ns]
+	mapperCacheMutex.RUnlock()
+	if ok {
+		return mapper
+	}
+	mapperCacheMutex.Lock()
+	defer mapperCacheMutex.Unlock()
+	if mapper, ok := mapperCache[ns]; ok {
+		return mapper
+	}
+	mapper = reflectx.NewMapperFunc("db", ns.ColumnName)
+	mapperCache[ns] = mapper
+	return mapper
+}
+
+// newModel wraps a value in a `Model` that follows the naming strategy of
+// the connection
+func (c *Connection) newModel(v interface{}) *Model {
+	return &Model{Value: v, naming: c.namingStrategy}
+}
+
+// namingStrategy returns the naming strategy of the model
+func (m *Model) namingStrategy() NamingStrategy {
+	if m.naming != nil {
+		return m.naming
+	}
+	return DefaultNamingStrategy
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 )
 
 // Bool replaces sql.NullBool with an implementation
@@ -57,7 +58,7 @@ func (ns Bool) MarshalJSON() ([]byte, error) {
 // the proper representation of that value. The strings
 // "true" and "t" will be considered "true", "false" and "f" will
 // be treated as "false". All other values will
-//be set to null by Valid = false
+// be set to null by Valid = false
 func (ns *Bool) UnmarshalJSON(text []byte) error {
 	t := string(text)
 	if t == "true" || t == "t" {
@@ -80,3 +81,48 @@ func (ns *Bool) UnmarshalJSON(text []byte) error {
 func (ns *Bool) UnmarshalText(text []byte) error {
 	return ns.UnmarshalJSON(text)
 }
+
+// null returns the value as a Null
+func (ns Bool) null() Null[bool] {
+	return Null[bool]{V: ns.Bool, Valid: ns.Valid}
+}
+
+// setNull sets the value from a Null
+func (ns *Bool) setNull(n Null[bool]) {
+	ns.Bool, ns.Valid = n.V, n.Valid
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ns Bool) MarshalText() ([]byte, error) {
+	return ns.null().MarshalText()
+}
+
+// MarshalYAML implements the yaml Marshaler interface.
+func (ns Bool) MarshalYAML() (interface{}, error) {
+	return ns.null().MarshalYAML()
+}
+
+// UnmarshalYAML implements the yaml Unmarshaler interface.
+func (ns *Bool) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return decode[bool](ns, func(n *Null[bool]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// MarshalXML implements the xml Marshaler interface.
+func (ns Bool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return ns.null().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements the xml Unmarshaler interface.
+func (ns *Bool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decode[bool](ns, func(n *Null[bool]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (ns Bool) MarshalBinary() ([]byte, error) {
+	return ns.null().MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (ns *Bool) UnmarshalBinary(data []byte) error {
+	return decode[bool](ns, func(n *Null[bool]) error { return n.UnmarshalBinary(data) })
+}
@@ -0,0 +1,11 @@
+package nulls
+
+// Byte is a byte that can be NULL. It is an alias of `Null[byte]`, the value
+// is in V.
+type Byte = Null[byte]
+
+// NewByte returns a new, properly instantiated
+// Byte object.
+func NewByte(b byte) Byte {
+	return NewNull(b)
+}
@@ -0,0 +1,11 @@
+package nulls
+
+// ByteSlice is a []byte that can be NULL. It is an alias of `Null[[]byte]`, the value
+// is in V.
+type ByteSlice = Null[[]byte]
+
+// NewByteSlice returns a new, properly instantiated
+// ByteSlice object.
+func NewByteSlice(b []byte) ByteSlice {
+	return NewNull(b)
+}
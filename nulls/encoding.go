@@ -0,0 +1,52 @@
+package nulls
+
+import (
+	"encoding/gob"
+	"encoding/xml"
+)
+
+// nullText is the text form of a null value in every text based encoding
+const nullText = "null"
+
+// xmlNullAttr marks an XML element that holds a null value
+var xmlNullAttr = xml.Attr{Name: xml.Name{Local: "null"}, Value: "true"}
+
+func init() {
+	gob.Register(Bool{})
+	gob.Register(Byte{})
+	gob.Register(ByteSlice{})
+	gob.Register(Float32{})
+	gob.Register(Float64{})
+	gob.Register(Int{})
+	gob.Register(Int32{})
+	gob.Register(Int64{})
+	gob.Register(String{})
+	gob.Register(Time{})
+	gob.Register(UInt32{})
+	gob.Register(UUID{})
+}
+
+// unmarshalYAML decodes a YAML value that may be null into T
+func unmarshalYAML[T any](unmarshal func(interface{}) error) (T, bool, error) {
+	var v *T
+	if err := unmarshal(&v); err != nil || v == nil {
+		var zero T
+		return zero, false, err
+	}
+	return *v, true, nil
+}
+
+// nullable is a named type of this package that converts to and from a
+// Null
+type nullable[T any] interface {
+	null() Null[T]
+	setNull(Null[T])
+}
+
+// decode runs fn on the Null form of ns and stores the result back
+func decode[T any](ns nullable[T], fn func(*Null[T]) error) error {
+	n := ns.null()
+	err := fn(&n)
+	ns.setNull(n)
+	return err
+}
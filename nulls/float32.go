@@ -0,0 +1,11 @@
+package nulls
+
+// Float32 is a float32 that can be NULL. It is an alias of `Null[float32]`, the value
+// is in V.
+type Float32 = Null[float32]
+
+// NewFloat32 returns a new, properly instantiated
+// Float32 object.
+func NewFloat32(f float32) Float32 {
+	return NewNull(f)
+}
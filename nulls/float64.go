@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 	"strconv"
 )
 
@@ -74,3 +75,48 @@ func (ns *Float64) UnmarshalJSON(text []byte) error {
 func (ns *Float64) UnmarshalText(text []byte) error {
 	return ns.UnmarshalJSON(text)
 }
+
+// null returns the value as a Null
+func (ns Float64) null() Null[float64] {
+	return Null[float64]{V: ns.Float64, Valid: ns.Valid}
+}
+
+// setNull sets the value from a Null
+func (ns *Float64) setNull(n Null[float64]) {
+	ns.Float64, ns.Valid = n.V, n.Valid
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ns Float64) MarshalText() ([]byte, error) {
+	return ns.null().MarshalText()
+}
+
+// MarshalYAML implements the yaml Marshaler interface.
+func (ns Float64) MarshalYAML() (interface{}, error) {
+	return ns.null().MarshalYAML()
+}
+
+// UnmarshalYAML implements the yaml Unmarshaler interface.
+func (ns *Float64) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return decode[float64](ns, func(n *Null[float64]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// MarshalXML implements the xml Marshaler interface.
+func (ns Float64) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return ns.null().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements the xml Unmarshaler interface.
+func (ns *Float64) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decode[float64](ns, func(n *Null[float64]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (ns Float64) MarshalBinary() ([]byte, error) {
+	return ns.null().MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (ns *Float64) UnmarshalBinary(data []byte) error {
+	return decode[float64](ns, func(n *Null[float64]) error { return n.UnmarshalBinary(data) })
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 	"strconv"
 )
 
@@ -57,10 +58,17 @@ func (ns Int) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON will unmarshal a JSON value into
 // the propert representation of that value.
 func (ns *Int) UnmarshalJSON(text []byte) error {
-	if i, err := strconv.ParseInt(string(text), 10, strconv.IntSize); err == nil {
-		ns.Valid = true
-		ns.Int = int(i)
+	ns.Int, ns.Valid = 0, false
+	t := string(text)
+	if t == "null" {
+		return nil
+	}
+	i, err := strconv.ParseInt(t, 10, strconv.IntSize)
+	if err != nil {
+		return err
 	}
+	ns.Valid = true
+	ns.Int = int(i)
 	return nil
 }
 
@@ -69,3 +77,48 @@ func (ns *Int) UnmarshalJSON(text []byte) error {
 func (ns *Int) UnmarshalText(text []byte) error {
 	return ns.UnmarshalJSON(text)
 }
+
+// null returns the value as a Null
+func (ns Int) null() Null[int] {
+	return Null[int]{V: ns.Int, Valid: ns.Valid}
+}
+
+// setNull sets the value from a Null
+func (ns *Int) setNull(n Null[int]) {
+	ns.Int, ns.Valid = n.V, n.Valid
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ns Int) MarshalText() ([]byte, error) {
+	return ns.null().MarshalText()
+}
+
+// MarshalYAML implements the yaml Marshaler interface.
+func (ns Int) MarshalYAML() (interface{}, error) {
+	return ns.null().MarshalYAML()
+}
+
+// UnmarshalYAML implements the yaml Unmarshaler interface.
+func (ns *Int) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return decode[int](ns, func(n *Null[int]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// MarshalXML implements the xml Marshaler interface.
+func (ns Int) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return ns.null().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements the xml Unmarshaler interface.
+func (ns *Int) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decode[int](ns, func(n *Null[int]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (ns Int) MarshalBinary() ([]byte, error) {
+	return ns.null().MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (ns *Int) UnmarshalBinary(data []byte) error {
+	return decode[int](ns, func(n *Null[int]) error { return n.UnmarshalBinary(data) })
+}
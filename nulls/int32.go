@@ -0,0 +1,11 @@
+package nulls
+
+// Int32 is an int32 that can be NULL. It is an alias of `Null[int32]`, the value
+// is in V.
+type Int32 = Null[int32]
+
+// NewInt32 returns a new, properly instantiated
+// Int32 object.
+func NewInt32(i int32) Int32 {
+	return NewNull(i)
+}
@@ -0,0 +1,11 @@
+package nulls
+
+// Int64 is an int64 that can be NULL. It is an alias of `Null[int64]`, the value
+// is in V.
+type Int64 = Null[int64]
+
+// NewInt64 returns a new, properly instantiated
+// Int64 object.
+func NewInt64(i int64) Int64 {
+	return NewNull(i)
+}
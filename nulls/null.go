@@ -0,0 +1,242 @@
+package nulls
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Null adds an implementation for any type that supports proper JSON
+// and text encoding/decoding. Int32, Int64, UInt32, Float32, Byte,
+// ByteSlice and UUID are aliases of it. Bool, Float64, Int, String and
+// Time predate it and keep their own fields, e.g. `Int.Int`, so they are
+// not aliases; they convert to a Null and share its text, YAML, XML and
+// binary encodings.
+//
+//	Age nulls.Null[int16] `db:"age"`
+type Null[T any] struct {
+	V     T
+	Valid bool // Valid is true if V is not NULL
+}
+
+// NewNull returns a new, properly instantiated
+// Null object.
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// Interface implements the nullable interface. It returns nil if
+// the value is not valid, otherwise it returns the value.
+func (ns Null[T]) Interface() interface{} {
+	if !ns.Valid {
+		return nil
+	}
+	return ns.V
+}
+
+// ValueType returns the type of the value held by the Null. It lets
+// schema generation map a Null to the column type of its type parameter.
+func (ns Null[T]) ValueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// OrElse returns the value if it is valid and def otherwise.
+func (ns Null[T]) OrElse(def T) T {
+	if !ns.Valid {
+		return def
+	}
+	return ns.V
+}
+
+// Scan implements the Scanner interface. The driver value is converted
+// to T the same way database/sql converts scanned columns.
+func (ns *Null[T]) Scan(value interface{}) error {
+	n := sql.Null[T]{}
+	if err := n.Scan(value); err != nil {
+		return err
+	}
+	ns.V, ns.Valid = n.V, n.Valid
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (ns Null[T]) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(ns.V)
+}
+
+// MarshalJSON marshals the underlying value to a
+// proper JSON representation.
+func (ns Null[T]) MarshalJSON() ([]byte, error) {
+	if ns.Valid {
+		return json.Marshal(ns.V)
+	}
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON will unmarshal a JSON value into
+// the propert representation of that value.
+func (ns *Null[T]) UnmarshalJSON(text []byte) error {
+	var zero T
+	ns.V, ns.Valid = zero, false
+	if string(text) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(text, &ns.V); err != nil {
+		return err
+	}
+	ns.Valid = true
+	return nil
+}
+
+// MarshalText marshals the underlying value to text. A null value is
+// "null", which a valid "null" string also marshals to; use the JSON,
+// XML or binary encodings where the two must be told apart.
+func (ns Null[T]) MarshalText() ([]byte, error) {
+	if !ns.Valid {
+		return []byte(nullText), nil
+	}
+	switch v := interface{}(ns.V).(type) {
+	case []byte:
+		return append([]byte{}, v...), nil
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	}
+	return []byte(fmt.Sprint(ns.V)), nil
+}
+
+// UnmarshalText will unmarshal text value into
+// the propert representation of that value.
+func (ns *Null[T]) UnmarshalText(text []byte) error {
+	if string(text) == nullText {
+		*ns = Null[T]{}
+		return nil
+	}
+	return ns.unmarshalValue(text)
+}
+
+// unmarshalValue sets a valid value from its text form. Unlike
+// UnmarshalText it never reads the text as null.
+func (ns *Null[T]) unmarshalValue(text []byte) error {
+	*ns = Null[T]{}
+	switch v := interface{}(&ns.V).(type) {
+	case *string:
+		*v = string(text)
+	case *[]byte:
+		*v = append([]byte{}, text...)
+	case *time.Time:
+		t, err := parseTimeString(string(text))
+		if err != nil {
+			return err
+		}
+		*v = t
+	case encoding.TextUnmarshaler:
+		if err := v.UnmarshalText(text); err != nil {
+			return err
+		}
+	default:
+		return ns.Scan(string(text))
+	}
+	ns.Valid = true
+	return nil
+}
+
+// yamlText reports whether the value is encoded in YAML by its text form
+// because YAML has no matching type
+func (ns Null[T]) yamlText() bool {
+	switch interface{}(&ns.V).(type) {
+	case *time.Time:
+		return false
+	case *[]byte, encoding.TextUnmarshaler:
+		return true
+	}
+	return false
+}
+
+// MarshalYAML implements the yaml Marshaler interface. A null value is
+// encoded as a YAML null.
+func (ns Null[T]) MarshalYAML() (interface{}, error) {
+	if !ns.Valid || !ns.yamlText() {
+		return ns.Interface(), nil
+	}
+	text, err := ns.MarshalText()
+	return string(text), err
+}
+
+// UnmarshalYAML implements the yaml Unmarshaler interface.
+func (ns *Null[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*ns = Null[T]{}
+	if !ns.yamlText() {
+		v, valid, err := unmarshalYAML[T](unmarshal)
+		ns.V, ns.Valid = v, valid
+		return err
+	}
+	text, valid, err := unmarshalYAML[string](unmarshal)
+	if err != nil || !valid {
+		return err
+	}
+	return ns.unmarshalValue([]byte(text))
+}
+
+// MarshalXML implements the xml Marshaler interface. A null value is
+// written as an empty element with a null="true" attribute, so a valid
+// "null" string keeps its value.
+func (ns Null[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !ns.Valid {
+		start.Attr = append(start.Attr, xmlNullAttr)
+		return e.EncodeElement("", start)
+	}
+	text, err := ns.MarshalText()
+	if err != nil {
+		return err
+	}
+	return e.EncodeElement(string(text), start)
+}
+
+// UnmarshalXML implements the xml Unmarshaler interface.
+func (ns *Null[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*ns = Null[T]{}
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	for _, attr := range start.Attr {
+		if attr.Name == xmlNullAttr.Name && attr.Value == xmlNullAttr.Value {
+			return nil
+		}
+	}
+	return ns.unmarshalValue([]byte(text))
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The
+// value is encoded as a validity byte followed by its text form.
+func (ns Null[T]) MarshalBinary() ([]byte, error) {
+	if !ns.Valid {
+		return []byte{0}, nil
+	}
+	text, err := ns.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{1}, text...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (ns *Null[T]) UnmarshalBinary(data []byte) error {
+	*ns = Null[T]{}
+	if len(data) == 0 {
+		return errors.New("nulls: empty binary data")
+	}
+	if data[0] == 0 {
+		return nil
+	}
+	return ns.unmarshalValue(data[1:])
+}
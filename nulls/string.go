@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 )
 
 // String replaces sql.NullString with an implementation
@@ -75,3 +76,48 @@ func (ns *String) UnmarshalText(text []byte) error {
 	ns.Valid = true
 	return nil
 }
+
+// null returns the value as a Null
+func (ns String) null() Null[string] {
+	return Null[string]{V: ns.String, Valid: ns.Valid}
+}
+
+// setNull sets the value from a Null
+func (ns *String) setNull(n Null[string]) {
+	ns.String, ns.Valid = n.V, n.Valid
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ns String) MarshalText() ([]byte, error) {
+	return ns.null().MarshalText()
+}
+
+// MarshalYAML implements the yaml Marshaler interface.
+func (ns String) MarshalYAML() (interface{}, error) {
+	return ns.null().MarshalYAML()
+}
+
+// UnmarshalYAML implements the yaml Unmarshaler interface.
+func (ns *String) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return decode[string](ns, func(n *Null[string]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// MarshalXML implements the xml Marshaler interface.
+func (ns String) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return ns.null().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements the xml Unmarshaler interface.
+func (ns *String) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decode[string](ns, func(n *Null[string]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (ns String) MarshalBinary() ([]byte, error) {
+	return ns.null().MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (ns *String) UnmarshalBinary(data []byte) error {
+	return decode[string](ns, func(n *Null[string]) error { return n.UnmarshalBinary(data) })
+}
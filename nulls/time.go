@@ -3,6 +3,10 @@ package nulls
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 )
 
@@ -28,12 +32,80 @@ func NewTime(t time.Time) Time {
 	return Time{Time: t, Valid: true}
 }
 
-// Scan implements the Scanner interface.
+// Scan implements the Scanner interface. Besides time.Time it accepts the
+// text and integer timestamps SQLite returns, see ParseTime.
 func (ns *Time) Scan(value interface{}) error {
-	ns.Time, ns.Valid = value.(time.Time)
+	ns.Time, ns.Valid = time.Time{}, false
+	if value == nil {
+		return nil
+	}
+	t, err := ParseTime(value)
+	if err != nil {
+		return err
+	}
+	ns.Time, ns.Valid = t, true
 	return nil
 }
 
+// timeFormats are the text layouts ParseTime accepts. Layouts without a
+// zone are read as UTC.
+var timeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"20060102",
+}
+
+// unixMillisThreshold tells apart epoch seconds from epoch milliseconds.
+// Seconds reach it in the year 33658.
+const unixMillisThreshold = 1e12
+
+// ParseTime converts a driver value to a time. It accepts time.Time,
+// RFC3339 and SQLite's `YYYY-MM-DD HH:MM:SS[.fff]` text, and Unix epoch
+// seconds or milliseconds as integers or floats. Text is never read as an
+// epoch, so digit-only text like `20200102` is a date.
+func ParseTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case int64:
+		return unixTime(v), nil
+	case float64:
+		if math.Abs(v) >= unixMillisThreshold {
+			return time.UnixMilli(int64(v)).UTC(), nil
+		}
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+	case []byte:
+		return parseTimeString(string(v))
+	case string:
+		return parseTimeString(v)
+	}
+	return time.Time{}, fmt.Errorf("cannot scan %T into nulls.Time", value)
+}
+
+func unixTime(v int64) time.Time {
+	if v >= unixMillisThreshold || v <= -unixMillisThreshold {
+		return time.UnixMilli(v).UTC()
+	}
+	return time.Unix(v, 0).UTC()
+}
+
+func parseTimeString(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as nulls.Time", s)
+}
+
 // Value implements the driver Valuer interface.
 func (ns Time) Value() (driver.Value, error) {
 	if !ns.Valid {
@@ -73,5 +145,60 @@ func (ns *Time) UnmarshalJSON(text []byte) error {
 // UnmarshalText will unmarshal text value into
 // the propert representation of that value.
 func (ns *Time) UnmarshalText(text []byte) error {
-	return ns.UnmarshalJSON(text)
+	ns.Time, ns.Valid = time.Time{}, false
+	txt := strings.Trim(string(text), `"`)
+	if txt == nullText || txt == "" {
+		return nil
+	}
+	t, err := parseTimeString(txt)
+	if err != nil {
+		return err
+	}
+	ns.Time, ns.Valid = t, true
+	return nil
+}
+
+// null returns the value as a Null
+func (ns Time) null() Null[time.Time] {
+	return Null[time.Time]{V: ns.Time, Valid: ns.Valid}
+}
+
+// setNull sets the value from a Null
+func (ns *Time) setNull(n Null[time.Time]) {
+	ns.Time, ns.Valid = n.V, n.Valid
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (ns Time) MarshalText() ([]byte, error) {
+	return ns.null().MarshalText()
+}
+
+// MarshalYAML implements the yaml Marshaler interface.
+func (ns Time) MarshalYAML() (interface{}, error) {
+	return ns.null().MarshalYAML()
+}
+
+// UnmarshalYAML implements the yaml Unmarshaler interface.
+func (ns *Time) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return decode[time.Time](ns, func(n *Null[time.Time]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// MarshalXML implements the xml Marshaler interface.
+func (ns Time) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return ns.null().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements the xml Unmarshaler interface.
+func (ns *Time) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decode[time.Time](ns, func(n *Null[time.Time]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (ns Time) MarshalBinary() ([]byte, error) {
+	return ns.null().MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (ns *Time) UnmarshalBinary(data []byte) error {
+	return decode[time.Time](ns, func(n *Null[time.Time]) error { return n.UnmarshalBinary(data) })
 }
@@ -0,0 +1,11 @@
+package nulls
+
+// UInt32 is a uint32 that can be NULL. It is an alias of `Null[uint32]`, the value
+// is in V.
+type UInt32 = Null[uint32]
+
+// NewUInt32 returns a new, properly instantiated
+// UInt32 object.
+func NewUInt32(i uint32) UInt32 {
+	return NewNull(i)
+}
@@ -0,0 +1,13 @@
+package nulls
+
+import uuid "github.com/satori/go.uuid"
+
+// UUID is a uuid.UUID that can be NULL. It is an alias of `Null[uuid.UUID]`, the value
+// is in V.
+type UUID = Null[uuid.UUID]
+
+// NewUUID returns a new, properly instantiated
+// UUID object.
+func NewUUID(u uuid.UUID) UUID {
+	return NewNull(u)
+}
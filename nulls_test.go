@@ -0,0 +1,174 @@
+package goala
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/estenssoros/goala/nulls"
+	uuid "github.com/satori/go.uuid"
+)
+
+type nullsRecord struct {
+	XMLName xml.Name           `xml:"record"`
+	Int     nulls.Int          `xml:"int"`
+	Bool    nulls.Bool         `xml:"bool"`
+	String  nulls.String       `xml:"string"`
+	Empty   nulls.String       `xml:"empty"`
+	Float64 nulls.Float64      `xml:"float64"`
+	Time    nulls.Time         `xml:"time"`
+	Int64   nulls.Int64        `xml:"int64"`
+	UInt32  nulls.UInt32       `xml:"uint32"`
+	Float32 nulls.Float32      `xml:"float32"`
+	Bytes   nulls.ByteSlice    `xml:"bytes"`
+	UUID    nulls.UUID         `xml:"uuid"`
+	Generic nulls.Null[string] `xml:"generic"`
+}
+
+func newNullsRecord() nullsRecord {
+	return nullsRecord{
+		Int:     nulls.NewInt(7),
+		Bool:    nulls.NewBool(true),
+		String:  nulls.NewString("null"),
+		Float64: nulls.NewFloat64(1.25),
+		Time:    nulls.NewTime(time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)),
+		Int64:   nulls.NewInt64(-1 << 40),
+		Float32: nulls.NewFloat32(0.5),
+		Bytes:   nulls.NewByteSlice([]byte("raw")),
+		UUID:    nulls.NewUUID(uuid.Must(uuid.NewV4())),
+		Generic: nulls.NewNull("x"),
+	}
+}
+
+func TestNullsXML(t *testing.T) {
+	want := newNullsRecord()
+	data, err := xml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, elem := range []string{`<uint32 null="true"></uint32>`, `<empty null="true"></empty>`, `<string>null</string>`} {
+		if !bytes.Contains(data, []byte(elem)) {
+			t.Errorf("want %s in: %s", elem, data)
+		}
+	}
+	have := nullsRecord{}
+	if err := xml.Unmarshal(data, &have); err != nil {
+		t.Fatal(err)
+	}
+	want.XMLName = have.XMLName
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want: %+v have: %+v", want, have)
+	}
+}
+
+func TestNullsAliases(t *testing.T) {
+	var n nulls.Null[int32] = nulls.NewInt32(3)
+	var i nulls.Int32 = n
+	if want, have := int32(3), i.V; !i.Valid || want != have {
+		t.Errorf("want: %d have: %+v", want, i)
+	}
+	var u nulls.Null[uuid.UUID] = nulls.UUID{}
+	if u.Valid {
+		t.Errorf("want null, have: %+v", u)
+	}
+}
+
+func TestNullsInvalidNumbers(t *testing.T) {
+	type record struct {
+		XMLName xml.Name      `xml:"record"`
+		Int     nulls.Int     `xml:"i"`
+		Int64   nulls.Int64   `xml:"i64"`
+		Float64 nulls.Float64 `xml:"f"`
+	}
+	for _, data := range []string{
+		"<record><i>abc</i></record>",
+		"<record><i64>abc</i64></record>",
+		"<record><f>abc</f></record>",
+	} {
+		if err := xml.Unmarshal([]byte(data), &record{}); err == nil {
+			t.Errorf("%s: expected parse error", data)
+		}
+	}
+	n := nulls.NewInt(1)
+	if err := n.UnmarshalText([]byte("abc")); err == nil || n.Valid {
+		t.Errorf("want parse error and null, have: %+v %v", n, err)
+	}
+	if err := n.UnmarshalText([]byte("null")); err != nil || n.Valid {
+		t.Errorf("want null, have: %+v %v", n, err)
+	}
+}
+
+func TestNullsGob(t *testing.T) {
+	want := newNullsRecord()
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	have := nullsRecord{}
+	if err := gob.NewDecoder(buf).Decode(&have); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want: %+v have: %+v", want, have)
+	}
+}
+
+func TestNullsTextAndYAML(t *testing.T) {
+	for _, tc := range []struct {
+		value interface{ MarshalText() ([]byte, error) }
+		text  string
+	}{
+		{nulls.NewInt(7), "7"},
+		{nulls.Int{}, "null"},
+		{nulls.NewFloat32(0.5), "0.5"},
+		{nulls.NewTime(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)), "2020-01-02T00:00:00Z"},
+		{nulls.UUID{}, "null"},
+	} {
+		text, err := tc.value.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(text) != tc.text {
+			t.Errorf("want: %s have: %s", tc.text, text)
+		}
+	}
+	// no YAML library is a dependency, so json.Unmarshal stands in for the
+	// decoder gopkg.in/yaml.v2 passes to UnmarshalYAML
+	yamlValue := func(data string) func(interface{}) error {
+		return func(v interface{}) error { return json.Unmarshal([]byte(data), v) }
+	}
+	n := nulls.NewInt64(3)
+	if err := n.UnmarshalYAML(yamlValue("null")); err != nil || n.Valid {
+		t.Errorf("want null, have: %+v %v", n, err)
+	}
+	if err := n.UnmarshalYAML(yamlValue("42")); err != nil || n != nulls.NewInt64(42) {
+		t.Errorf("want 42, have: %+v %v", n, err)
+	}
+	if v, _ := n.MarshalYAML(); v != int64(42) {
+		t.Errorf("want 42, have: %v", v)
+	}
+	if v, _ := (nulls.Int64{}).MarshalYAML(); v != nil {
+		t.Errorf("want nil, have: %v", v)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, v := range []interface{}{"20200102", []byte("2020-01-02"), want.Unix(), want.UnixMilli(), float64(want.Unix())} {
+		have, err := nulls.ParseTime(v)
+		if err != nil {
+			t.Errorf("%v: %v", v, err)
+			continue
+		}
+		if !want.Equal(have) {
+			t.Errorf("%v: want: %v have: %v", v, want, have)
+		}
+	}
+	if _, err := nulls.ParseTime("1577923200"); err == nil {
+		t.Error("want an error for epoch seconds as text")
+	}
+}
@@ -1,20 +1,30 @@
 package goala
 
 import (
+	"context"
 	"fmt"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // Query is the main value that is used to build up a query
 // to be executed against the `Connection`.
 type Query struct {
-	RawSQL       *clause
-	limitResults int
-	addColumns   []string
-	whereClauses clauses
-	orderClauses clauses
-	fromClauses  fromClauses
-	Connection   *Connection
+	RawSQL         *clause
+	limitResults   int
+	addColumns     []string
+	excludeColumns []string
+	whereClauses   clauses
+	orderClauses   clauses
+	fromClauses    fromClauses
+	model          *Model
+	alias          string
+	namedScopes    []string
+	unscoped       bool
+	ctx            context.Context
+	err            error
+	Connection     *Connection
 }
 
 // First wraps first query
@@ -25,11 +35,15 @@ func (c *Connection) First(model interface{}) error {
 // First executes select one
 func (q *Query) First(model interface{}) error {
 	q.Limit(1)
-	m := &Model{Value: model}
-	if err := q.Connection.Dialect.SelectOne(q.Connection.DB, m, *q); err != nil {
+	m := q.Connection.newModel(model)
+	query, err := q.resolveScopes(m)
+	if err != nil {
 		return err
 	}
-	return nil
+	if err := q.Connection.Dialect.SelectOne(q.Connection.db(), m, query); err != nil {
+		return err
+	}
+	return m.afterFind(q.Connection)
 }
 
 // RawQuery will override the query building feature and will use
@@ -52,26 +66,35 @@ func (q *Query) RawQuery(stmt string, args ...interface{}) *Query {
 }
 
 // Where will append a where clause to the query. You may use `?` in place of
-// arguments.
+// arguments. An argument may also be a `*Query`, which is compiled as a
+// subquery.
 //
 // 	c.Where("id = ?", 1)
 // 	q.Where("id in (?)", 1, 2, 3)
+//	c.Where("user_id in (?)", c.Select("id").From(&User{}).Where("active = ?", true))
 func (c *Connection) Where(stmt string, args ...interface{}) *Query {
 	q := Q(c)
 	return q.Where(stmt, args...)
 }
 
 // Where will append a where clause to the query. You may use `?` in place of
-// arguments.
+// arguments. An argument may also be a `*Query`, which is compiled as a
+// subquery.
 //
 // 	q.Where("id = ?", 1)
 // 	q.Where("id in (?)", 1, 2, 3)
+//	q.Where("user_id in (?)", c.Select("id").From(&User{}).Where("active = ?", true))
 func (q *Query) Where(stmt string, args ...interface{}) *Query {
 	if q.RawSQL.Fragment != "" {
 		fmt.Println("Warning: Query is setup to use raw SQL")
 		return q
 	}
-	if inRegex.MatchString(stmt) {
+	stmt, args, hasSubquery, err := expandSubqueries(stmt, args)
+	if err != nil {
+		q.addError(errors.Wrap(err, "where"))
+		return q
+	}
+	if !hasSubquery && inRegex.MatchString(stmt) {
 		var inq []string
 		for i := 0; i < len(args); i++ {
 			inq = append(inq, "?")
@@ -102,6 +125,51 @@ func (q *Query) Order(stmt string) *Query {
 	return q
 }
 
+// Select restricts the columns returned by the query. Fields of the model
+// whose columns are not selected are left at their zero value.
+//
+//	c.Select("id", "name")
+func (c *Connection) Select(columns ...string) *Query {
+	return Q(c).Select(columns...)
+}
+
+// Select restricts the columns returned by the query. Fields of the model
+// whose columns are not selected are left at their zero value.
+//
+//	q.Select("id", "name")
+func (q *Query) Select(columns ...string) *Query {
+	q.addColumns = append(q.addColumns, columns...)
+	return q
+}
+
+// Exclude removes columns from the ones the query would otherwise select.
+//
+//	c.Exclude("blob")
+func (c *Connection) Exclude(columns ...string) *Query {
+	return Q(c).Exclude(columns...)
+}
+
+// Exclude removes columns from the ones the query would otherwise select.
+//
+//	q.Exclude("blob")
+func (q *Query) Exclude(columns ...string) *Query {
+	q.excludeColumns = append(q.excludeColumns, columns...)
+	return q
+}
+
+// Unscoped includes soft deleted records in the query.
+func (c *Connection) Unscoped() *Query {
+	return Q(c).Unscoped()
+}
+
+// Unscoped includes soft deleted records in the query.
+//
+//	q.Unscoped().Where("deleted_at IS NOT NULL").All(&[]User{})
+func (q *Query) Unscoped() *Query {
+	q.unscoped = true
+	return q
+}
+
 // Limit will add a limit clause to the query.
 func (c *Connection) Limit(limit int) *Query {
 	return Q(c).Limit(limit)
@@ -113,6 +181,14 @@ func (q *Query) Limit(limit int) *Query {
 	return q
 }
 
+// addError records the first error found while building the query. It is
+// returned when the query is executed.
+func (q *Query) addError(err error) {
+	if err != nil && q.err == nil {
+		q.err = err
+	}
+}
+
 // Q will create a new "empty" query from the current connection.
 func Q(c *Connection) *Query {
 	return &Query{
@@ -122,16 +198,34 @@ func Q(c *Connection) *Query {
 }
 
 // ToSQL will generate SQL and the appropriate arguments for that SQL
-// from the `Model` passed in.
+// from the `Model` passed in. An invalid query generates no SQL, use
+// `ToSQLErr` to get its error.
 func (q Query) ToSQL(model *Model) (string, []interface{}) {
-	sb := q.toSQLBuilder(model)
-	return sb.String(), sb.Args()
+	sql, args, _ := q.ToSQLErr(model)
+	return sql, args
+}
+
+// ToSQLErr is `ToSQL` returning the first error found while building the
+// query, such as an unknown scope or a subquery without a source.
+func (q Query) ToSQLErr(model *Model) (string, []interface{}, error) {
+	sb, err := q.toSQLBuilder(model)
+	if err != nil {
+		return "", nil, err
+	}
+	return sb.String(), sb.Args(), nil
 }
 
 // ToSQLBuilder returns a new `SQLBuilder` that can be used to generate SQL,
 // get arguments, and more.
-func (q Query) toSQLBuilder(model *Model) *sqlBuilder {
-	return newSQLBuilder(q, model)
+func (q Query) toSQLBuilder(model *Model) (*sqlBuilder, error) {
+	if model == nil {
+		model = q.model
+	}
+	q, err := q.resolveScopes(model)
+	if err != nil {
+		return nil, err
+	}
+	return newSQLBuilder(q, model), nil
 }
 
 // All retrieves all of the records in the database that match the query.
@@ -145,77 +239,278 @@ func (c *Connection) All(models interface{}) error {
 //
 //	q.Where("name = ?", "mark").All(&[]User{})
 func (q *Query) All(models interface{}) error {
-	m := &Model{Value: models}
-	if err := q.Connection.Dialect.SelectMany(q.Connection.DB, m, *q); err != nil {
+	m := q.Connection.newModel(models)
+	query, err := q.resolveScopes(m)
+	if err != nil {
 		return err
 	}
-	return nil
+	if err := q.Connection.Dialect.SelectMany(q.Connection.db(), m, query); err != nil {
+		return err
+	}
+	return m.iterate(func(m *Model) error {
+		return m.afterFind(q.Connection)
+	})
 }
 
 // Create inserts a new model or slice of models
 func (c *Connection) Create(model interface{}) error {
-	sm := &Model{Value: model}
+	sm := c.newModel(model)
 	return sm.iterate(func(m *Model) error {
-		if err := c.Dialect.Create(c.DB, m); err != nil {
+		if err := m.beforeCreateHooks(c); err != nil {
+			return err
+		}
+		if err := c.prepareCreate(m); err != nil {
+			return err
+		}
+		if err := c.Dialect.Create(c.db(), m); err != nil {
 			return err
 		}
-		return nil
+		return m.afterCreateHooks(c)
 	})
 }
 
 // CreateMany inserts a new model or slice of models
 func (c *Connection) CreateMany(model interface{}) error {
-	sm := &Model{Value: model}
-	if err := c.Dialect.CreateMany(c.DB, sm); err != nil {
+	sm := c.newModel(model)
+	before := func(m *Model, c *Connection) error {
+		if err := m.beforeCreateHooks(c); err != nil {
+			return err
+		}
+		return c.prepareCreate(m)
+	}
+	return c.withHooks(sm, before, func() error {
+		return c.Dialect.CreateMany(c.db(), sm)
+	}, (*Model).afterCreateHooks)
+}
+
+// prepareCreate sets the id and the created and updated timestamps of a
+// new model
+func (c *Connection) prepareCreate(m *Model) error {
+	if err := m.setID(c.idGenerator(m)); err != nil {
 		return err
 	}
+	now := c.now()
+	m.touchCreatedAt(now)
+	m.touchUpdatedAt(now)
 	return nil
 }
 
-// Destroy deletes a given entry from the database
+// Destroy deletes a given entry from the database. Models with a
+// `DeletedAt` field are soft deleted by setting it instead.
 func (c *Connection) Destroy(model interface{}) error {
-	sm := &Model{Value: model}
+	sm := c.newModel(model)
+	if !sm.isSoftDeletable() {
+		return c.HardDestroy(model)
+	}
+	now := c.now()
 	return sm.iterate(func(m *Model) error {
-		if err := c.Dialect.Destroy(c.DB, m); err != nil {
+		if err := m.beforeDestroy(c); err != nil {
 			return err
 		}
-		return nil
+		if err := c.Dialect.SoftDestroy(c.db(), m, now); err != nil {
+			return err
+		}
+		return m.afterDestroy(c)
 	})
 }
 
-// DestroyMany deletes many entries from a database
+// HardDestroy deletes a given entry from the database even if the model
+// supports soft deletes
+func (c *Connection) HardDestroy(model interface{}) error {
+	sm := c.newModel(model)
+	return sm.iterate(func(m *Model) error {
+		if err := m.beforeDestroy(c); err != nil {
+			return err
+		}
+		if err := c.Dialect.Destroy(c.db(), m); err != nil {
+			return err
+		}
+		return m.afterDestroy(c)
+	})
+}
+
+// DestroyMany deletes many entries from a database. Models with a
+// `DeletedAt` field are soft deleted by setting it instead.
 func (c *Connection) DestroyMany(models interface{}) error {
-	m := &Model{Value: models}
-	if err := c.Dialect.DestroyMany(c.DB, m); err != nil {
+	m := c.newModel(models)
+	if !m.isSoftDeletable() {
+		return c.HardDestroyMany(models)
+	}
+	return c.withHooks(m, (*Model).beforeDestroy, func() error {
+		return c.Dialect.SoftDestroyMany(c.db(), m, c.now())
+	}, (*Model).afterDestroy)
+}
+
+// HardDestroyMany deletes many entries from a database even if the model
+// supports soft deletes
+func (c *Connection) HardDestroyMany(models interface{}) error {
+	m := c.newModel(models)
+	return c.withHooks(m, (*Model).beforeDestroy, func() error {
+		return c.Dialect.DestroyMany(c.db(), m)
+	}, (*Model).afterDestroy)
+}
+
+// withHooks runs the before hook on every model, then fn once for all of
+// them and finally the after hook on every model
+func (c *Connection) withHooks(model *Model, before func(*Model, *Connection) error, fn func() error, after func(*Model, *Connection) error) error {
+	if err := model.iterate(func(m *Model) error {
+		return before(m, c)
+	}); err != nil {
 		return err
 	}
-	return nil
+	if err := fn(); err != nil {
+		return err
+	}
+	return model.iterate(func(m *Model) error {
+		return after(m, c)
+	})
 }
 
 // Update updates a record
 func (c *Connection) Update(model interface{}) error {
-	sm := &Model{Value: model}
-	return sm.iterate(func(m *Model) error {
-		var err error
-		m.touchUpdatedAt()
-		if err = c.Dialect.Update(c.DB, m); err != nil {
+	sm := c.newModel(model)
+	return c.update(sm, func(m *Model) error {
+		return c.Dialect.Update(c.db(), m)
+	})
+}
+
+// UpdateColumns updates only the given columns of a record. The
+// `updated_at` column is written as well when the model has one.
+//
+//	c.UpdateColumns(&user, "name", "email")
+func (c *Connection) UpdateColumns(model interface{}, columns ...string) error {
+	sm := c.newModel(model)
+	if col, ok := sm.timestampColumn(updatedTimestamp); ok && !isin(columns, col) {
+		columns = append(columns, col)
+	}
+	modelCols := sm.ColumnSlice()
+	for _, col := range columns {
+		if !isin(modelCols, col) {
+			return errors.Errorf("update columns: %s: missing column: %s", sm.TableName(), col)
+		}
+	}
+	return c.update(sm, func(m *Model) error {
+		return c.Dialect.UpdateColumns(c.db(), m, columns)
+	})
+}
+
+// UpdateExcept updates every column of a record except the given ones.
+//
+//	c.UpdateExcept(&user, "password")
+func (c *Connection) UpdateExcept(model interface{}, columns ...string) error {
+	sm := c.newModel(model)
+	modelCols := sm.ColumnSlice()
+	for _, col := range columns {
+		if !isin(modelCols, col) {
+			return errors.Errorf("update except: %s: missing column: %s", sm.TableName(), col)
+		}
+	}
+	cols := []string{}
+	for _, col := range sm.updateColumnSlice() {
+		if !isin(columns, col) {
+			cols = append(cols, col)
+		}
+	}
+	return c.update(sm, func(m *Model) error {
+		return c.Dialect.UpdateColumns(c.db(), m, cols)
+	})
+}
+
+// update touches and updates the model or each model of a slice, running
+// the update hooks around fn
+func (c *Connection) update(model *Model, fn modelIterable) error {
+	return model.iterate(func(m *Model) error {
+		if err := m.beforeUpdateHooks(c); err != nil {
 			return err
 		}
-		return nil
+		m.touchUpdatedAt(c.now())
+		if err := fn(m); err != nil {
+			return err
+		}
+		return m.afterUpdateHooks(c)
 	})
 }
 
+// UpdateAll sets the values on every record matched by the query and
+// returns the number of records updated. The table is taken from the model
+// passed to `From` and every key of values must be one of its columns.
+//
+//	c.From(&User{}).Where("team_id = ?", id).UpdateAll(map[string]interface{}{"active": false})
+func (q *Query) UpdateAll(values map[string]interface{}) (int64, error) {
+	if q.model == nil {
+		return 0, errors.New("update all: query is missing a model, use From")
+	}
+	modelCols := q.model.ColumnSlice()
+	for col := range values {
+		if !isin(modelCols, col) {
+			return 0, errors.Errorf("update all: %s: missing column: %s", q.model.TableName(), col)
+		}
+	}
+	if col, ok := q.model.timestampColumn(updatedTimestamp); ok {
+		if _, set := values[col]; !set {
+			withUpdatedAt := map[string]interface{}{col: q.Connection.now()}
+			for k, v := range values {
+				withUpdatedAt[k] = v
+			}
+			values = withUpdatedAt
+		}
+	}
+	query, err := q.resolveScopes(q.model)
+	if err != nil {
+		return 0, err
+	}
+	return q.Connection.Dialect.UpdateAll(q.Connection.db(), q.model, query, values)
+}
+
+// Delete deletes every record of the model's table matched by the query in
+// a single statement and returns the number of records deleted. Models with
+// a `DeletedAt` field are soft deleted unless the query is `Unscoped`. A
+// query without where clauses is refused, use `DeleteAll` to delete every
+// record. Limit is not supported.
+//
+//	c.Where("expires_at < ?", now).Delete(&Session{})
+func (q *Query) Delete(model interface{}) (int64, error) {
+	return q.delete(model, false)
+}
+
+// DeleteAll is `Delete` without the need for a where clause. It deletes
+// every record of the model's table when the query has none.
+//
+//	Q(c).DeleteAll(&Session{})
+func (q *Query) DeleteAll(model interface{}) (int64, error) {
+	return q.delete(model, true)
+}
+
+func (q *Query) delete(model interface{}, all bool) (int64, error) {
+	m := q.Connection.newModel(model)
+	query, err := q.resolveScopes(m)
+	if err != nil {
+		return 0, err
+	}
+	if query.limitResults > 0 {
+		return 0, errors.New("delete: limit is not supported")
+	}
+	if !all && len(query.whereClauses) == 0 {
+		return 0, errors.Errorf("delete: %s: query has no where clauses, use DeleteAll", m.TableName())
+	}
+	if col, ok := m.deletedAtColumn(); ok && !query.unscoped {
+		values := map[string]interface{}{col: q.Connection.now()}
+		return q.Connection.Dialect.UpdateAll(q.Connection.db(), m, query, values)
+	}
+	return q.Connection.Dialect.DeleteWhere(q.Connection.db(), m, query)
+}
+
 func (c *Connection) SQLView(model interface{}, format map[string]string) error {
-	m := &Model{Value: model}
-	if err := c.Dialect.SQLView(c.DB, m, format); err != nil {
+	m := c.newModel(model)
+	if err := c.Dialect.SQLView(c.db(), m, format); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (c *Connection) CreateTable(model interface{}) error {
-	m := &Model{Value: model}
-	if err := c.Dialect.CreateTable(c.DB, m); err != nil {
+	m := c.newModel(model)
+	if err := c.Dialect.CreateTable(c.db(), m); err != nil {
 		return err
 	}
 	return nil
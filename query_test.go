@@ -0,0 +1,142 @@
+package goala
+
+import (
+	"testing"
+
+	"github.com/estenssoros/goala/nulls"
+)
+
+func newTestQuery() *Query {
+	return Q(&Connection{Dialect: &sqlite3{}})
+}
+
+func mustSQL(t *testing.T, q *Query, m *Model) (string, []interface{}) {
+	t.Helper()
+	sql, args, err := q.ToSQLErr(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sql, args
+}
+
+func TestSelectColumns(t *testing.T) {
+	m := &Model{Value: newTestStruct()}
+	sql, _ := newTestQuery().Select("id_field", "string_field").ToSQL(m)
+	if want, have := "SELECT id_field,string_field FROM test", sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	sql, _ = newTestQuery().Exclude("time_field", "null_string", "null_float", "null_int", "null_bool").ToSQL(m)
+	if want, have := "SELECT id_field,string_field,float_field,int_field,bool_field FROM test", sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	sql, _ = newTestQuery().ToSQL(m)
+	if want, have := "SELECT "+m.Columns()+" FROM test", sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+}
+
+func TestWhereSubquery(t *testing.T) {
+	m := &Model{Value: newTestStruct()}
+	sub := newTestQuery().Select("id_field").From("other").Where("int_field > ?", 3)
+	sql, args := mustSQL(t, newTestQuery().Select("id_field").Where("bool_field = ? AND id_field IN (?) AND int_field = ?", true, sub, 7), m)
+	if want, have := "SELECT id_field FROM test WHERE bool_field = ? AND id_field IN (SELECT id_field FROM other WHERE int_field > ?) AND int_field = ?", sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := []interface{}{true, 3, 7}, args; len(want) != len(have) || want[0] != have[0] || want[1] != have[1] || want[2] != have[2] {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	sql, _ = mustSQL(t, newTestQuery().Where("int_field = ?", newTestQuery().Select("max(int_field)").From(m.Value)), m)
+	if want, have := "SELECT "+m.Columns()+" FROM test WHERE int_field = (SELECT max(int_field) FROM test)", sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	sql, args = mustSQL(t, newTestQuery().Select("id_field").WhereCond(Or(In("id_field", sub), Expr("int_field = ?", 7))), m)
+	if want, have := `SELECT id_field FROM test WHERE ("id_field" IN (SELECT id_field FROM other WHERE int_field > ?) OR int_field = ?)`, sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := 2, len(args); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func TestFromSubquery(t *testing.T) {
+	m := &Model{Value: newTestStruct()}
+	sub := newTestQuery().From(m.Value).Where("int_field > ?", 3).As("t")
+	sql, args := mustSQL(t, newTestQuery().Select("id_field").From(sub).Where("bool_field = ?", true), m)
+	if want, have := "SELECT id_field FROM (SELECT "+m.Columns()+" FROM test WHERE int_field > ?) AS t WHERE bool_field = ?", sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := 2, len(args); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func TestSubqueryWithoutFrom(t *testing.T) {
+	m := &Model{Value: newTestStruct()}
+	sub := newTestQuery().Select("id_field")
+	if _, _, err := newTestQuery().From(sub).ToSQLErr(m); err == nil {
+		t.Error("expected error for subquery without From")
+	}
+	if _, _, err := newTestQuery().Where("id_field IN (?)", sub).ToSQLErr(m); err == nil {
+		t.Error("expected error for subquery without From")
+	}
+	if _, _, err := newTestQuery().WhereCond(In("id_field", sub)).ToSQLErr(m); err == nil {
+		t.Error("expected error for subquery without From")
+	}
+	sub = newTestQuery().Select("id_field").From("other")
+	if _, _, err := newTestQuery().Where("int_field = ?", 1, sub).ToSQLErr(m); err == nil {
+		t.Error("expected error for more arguments than placeholders")
+	}
+}
+
+func TestWhereCond(t *testing.T) {
+	m := &Model{Value: newTestStruct()}
+	cond := Or(
+		Eq{"string_field": "a", "null_int": nil},
+		And(In("int_field", []int{1, 2}), Not(Like("string_field", "b%"))),
+		Between("float_field", 1.0, 2.0),
+	)
+	sql, args := mustSQL(t, newTestQuery().Select("id_field").WhereCond(cond, In("id_field", []string{})), m)
+	want := `SELECT id_field FROM test WHERE (("null_int" IS NULL AND "string_field" = ?) OR ("int_field" IN (?,?) AND NOT ("string_field" LIKE ?)) OR "float_field" BETWEEN ? AND ?) AND 1=0`
+	if have := sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := 6, len(args); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+func TestWhereStruct(t *testing.T) {
+	m := &Model{Value: newTestStruct()}
+	example := &TestStruct{StringField: "asdf", NullIntField: nulls.NewInt(0)}
+	sql, args := mustSQL(t, newTestQuery().Select("id_field").WhereStruct(example), m)
+	if want, have := `SELECT id_field FROM test WHERE ("null_int" = ? AND "string_field" = ?)`, sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := 2, len(args); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	sql, _ = mustSQL(t, newTestQuery().Select("id_field").WhereStruct(example, IncludeZero("int_field", "null_bool")), m)
+	if want, have := `SELECT id_field FROM test WHERE ("int_field" = ? AND "null_bool" IS NULL AND "null_int" = ? AND "string_field" = ?)`, sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+}
+
+func TestScopes(t *testing.T) {
+	m := &Model{Value: newTestStruct()}
+	positive := func(q *Query) *Query {
+		return q.Where("int_field > ?", 0)
+	}
+	RegisterScope(&TestStruct{}, "newest", func(q *Query) *Query {
+		return q.Order("time_field desc")
+	})
+	sql, _ := mustSQL(t, newTestQuery().Select("id_field").Scope(positive).Scopes("newest"), m)
+	if want, have := "SELECT id_field FROM test WHERE int_field > ? ORDER BY time_field desc", sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if _, err := newTestQuery().Scopes("missing").resolveScopes(m); err == nil {
+		t.Error("expected error for missing scope")
+	}
+	if _, _, err := newTestQuery().Scopes("missing").ToSQLErr(m); err == nil {
+		t.Error("expected error for missing scope")
+	}
+}
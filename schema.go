@@ -3,8 +3,11 @@ package goala
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/estenssoros/goala/nulls"
+	"github.com/estenssoros/goala/values"
 	"github.com/pkg/errors"
 )
 
@@ -20,7 +23,7 @@ func (s Schema) String() string {
 }
 
 func (m *Model) CreateSchema() (*Schema, error) {
-	return createSchema(m.Value)
+	return newSchema(m)
 }
 
 func (s *Schema) AddColumn(name string, dataType int) error {
@@ -48,13 +51,18 @@ func (s *Schema) Len() int {
 }
 
 func (s *Schema) SQL() (string, error) {
+	return s.SQLFor("sqlite3")
+}
+
+// SQLFor returns the create table statement of the schema for a dialect
+func (s *Schema) SQLFor(dialect string) (string, error) {
 	clauses := make([]string, s.Len())
 	for i, name := range s.Order {
 		c, err := s.GetColumn(name)
 		if err != nil {
 			return "", errors.Wrap(err, "schema: sql")
 		}
-		sql, err := c.SQL()
+		sql, err := c.SQLFor(dialect)
 		if err != nil {
 			return "", errors.Wrap(err, "schema: sql")
 		}
@@ -67,6 +75,8 @@ type Column struct {
 	Name     string
 	DataType int
 	Length   int
+	SQLType  string
+	Default  values.Value
 }
 
 func (c Column) String() string {
@@ -77,35 +87,108 @@ func (c Column) String() string {
 func NewColumn(name string, dataType int) (*Column, error) {
 	switch dataType {
 	case StringType, NullsStringType:
-		return &Column{name, dataType, 50}, nil
+		return &Column{name, dataType, 50, "", nil}, nil
 	case IntType, NullsIntType:
-		return &Column{name, dataType, -1}, nil
+		return &Column{name, dataType, -1, "", nil}, nil
 	case FloatType, NullsFloatType:
-		return &Column{name, dataType, -1}, nil
+		return &Column{name, dataType, -1, "", nil}, nil
 	case BoolType, NullsBoolType:
-		return &Column{name, dataType, -1}, nil
+		return &Column{name, dataType, -1, "", nil}, nil
 	case TimeType, NullsTimeType:
-		return &Column{name, dataType, -1}, nil
+		return &Column{name, dataType, -1, "", nil}, nil
 	case UUIDType:
-		return &Column{name, dataType, 36}, nil
+		return &Column{name, dataType, 36, "", nil}, nil
+	case ValuerType, BytesType, JSONType:
+		return &Column{name, dataType, -1, "", nil}, nil
+	case NullsInt32Type, NullsInt64Type, NullsUInt32Type, NullsByteType:
+		return &Column{name, dataType, -1, "", nil}, nil
+	case NullsFloat32Type, NullsByteSliceType:
+		return &Column{name, dataType, -1, "", nil}, nil
+	case NullsUUIDType:
+		return &Column{name, dataType, 36, "", nil}, nil
 	}
 	return nil, errors.Errorf("missing datatype: %d", dataType)
 }
 
 func (c *Column) SQL() (string, error) {
+	return c.SQLFor("sqlite3")
+}
+
+// SQLFor returns the column definition for a dialect
+func (c *Column) SQLFor(dialect string) (string, error) {
+	sql, err := c.typeSQL(dialect)
+	if err != nil || c.Default == nil {
+		return sql, err
+	}
+	return fmt.Sprintf("%s DEFAULT %s", sql, c.Default.Literal(dialect)), nil
+}
+
+func (c *Column) typeSQL(dialect string) (string, error) {
+	if c.SQLType != "" {
+		return fmt.Sprintf("%s %s", c.Name, c.SQLType), nil
+	}
 	switch c.DataType {
 	case StringType, NullsStringType:
 		return fmt.Sprintf("%s TEXT", c.Name), nil
-	case IntType, NullsIntType:
+	case IntType, NullsIntType, NullsInt32Type, NullsInt64Type, NullsUInt32Type, NullsByteType:
 		return fmt.Sprintf("%s INT", c.Name), nil
-	case FloatType, NullsFloatType:
+	case FloatType, NullsFloatType, NullsFloat32Type:
 		return fmt.Sprintf("%s NUMERIC", c.Name), nil
 	case BoolType, NullsBoolType:
 		return fmt.Sprintf("%s INT", c.Name), nil
 	case TimeType, NullsTimeType:
-		return fmt.Sprintf("%s NUMERIC", c.Name), nil
-	case UUIDType:
+		return fmt.Sprintf("%s DATETIME", c.Name), nil
+	case UUIDType, ValuerType, NullsUUIDType:
+		return fmt.Sprintf("%s TEXT", c.Name), nil
+	case BytesType, NullsByteSliceType:
+		return fmt.Sprintf("%s BLOB", c.Name), nil
+	case JSONType:
+		if dialect == "postgres" {
+			return fmt.Sprintf("%s JSONB", c.Name), nil
+		}
 		return fmt.Sprintf("%s TEXT", c.Name), nil
 	}
 	return "", errors.Errorf("missing datatype: %d", c.DataType)
 }
+
+// ParseDefault parses the text of a `default` tag into a value of the data
+// type of the column. "NULL" is the null value.
+//
+//	Status string `db:"status" default:"active"`
+func (c *Column) ParseDefault(text string) error {
+	if strings.EqualFold(text, "null") {
+		c.Default = values.Null()
+		return nil
+	}
+	switch c.DataType {
+	case IntType, NullsIntType, NullsInt32Type, NullsInt64Type, NullsUInt32Type, NullsByteType:
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "column %s: default", c.Name)
+		}
+		c.Default = values.Int(i)
+	case FloatType, NullsFloatType, NullsFloat32Type:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return errors.Wrapf(err, "column %s: default", c.Name)
+		}
+		c.Default = values.Float(f)
+	case BoolType, NullsBoolType:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return errors.Wrapf(err, "column %s: default", c.Name)
+		}
+		c.Default = values.Bool(b)
+	case TimeType, NullsTimeType:
+		t, err := nulls.ParseTime(text)
+		if err != nil {
+			return errors.Wrapf(err, "column %s: default", c.Name)
+		}
+		c.Default = values.Time(t)
+	case BytesType, NullsByteSliceType:
+		c.Default = values.Bytes([]byte(text))
+	default:
+		c.Default = values.String(text)
+	}
+	return nil
+}
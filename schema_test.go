@@ -1,6 +1,10 @@
 package goala
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -75,3 +79,62 @@ func TestCreateSchemaModel(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+type tier int
+
+func (t tier) Value() (driver.Value, error) {
+	return fmt.Sprintf("tier-%d", t), nil
+}
+
+func (t *tier) Scan(src interface{}) error {
+	_, err := fmt.Sscanf(fmt.Sprintf("%s", src), "tier-%d", (*int)(t))
+	return err
+}
+
+type cents int64
+
+type color string
+
+func (color) ColumnType() ColumnType {
+	return ColumnType{DataType: StringType, SQL: "VARCHAR(7)"}
+}
+
+type customTypeStruct struct {
+	ID    uuid.UUID       `db:"id"`
+	Tier  tier            `db:"tier"`
+	Price cents           `db:"price"`
+	Color color           `db:"color"`
+	Meta  json.RawMessage `db:"meta"`
+}
+
+func (customTypeStruct) TableName() string { return "custom" }
+
+func TestCustomColumnTypes(t *testing.T) {
+	RegisterType(reflect.TypeOf(cents(0)), ColumnType{DataType: IntType, SQL: "DECIMAL(10,2)"})
+	schema, err := createSchema(&customTypeStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, err := schema.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := "CREATE TABLE custom (id TEXT,tier TEXT,price DECIMAL(10,2),color VARCHAR(7),meta BLOB)", sql; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&customTypeStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateMany(&[]customTypeStruct{{Tier: 3, Price: 250, Color: "#fff", Meta: json.RawMessage(`{"a":1}`)}}); err != nil {
+		t.Fatal(err)
+	}
+	found := &customTypeStruct{}
+	if err := db.First(found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Tier != 3 || found.Price != 250 || found.Color != "#fff" || string(found.Meta) != `{"a":1}` {
+		t.Errorf("unexpected record: %+v", found)
+	}
+}
@@ -0,0 +1,117 @@
+package goala
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ScopeFunc is a reusable query fragment. It receives the query being built
+// and returns it with its clauses added.
+//
+//	func Active(q *Query) *Query {
+//		return q.Where("deleted_at IS NULL").Order("created_at desc")
+//	}
+type ScopeFunc func(*Query) *Query
+
+// Scope applies the scopes to a new query.
+//
+//	c.Scope(Active).All(&[]User{})
+func (c *Connection) Scope(scopes ...ScopeFunc) *Query {
+	return Q(c).Scope(scopes...)
+}
+
+// Scope applies the scopes to the query.
+//
+//	q.Scope(Active, Paginate(2)).All(&[]User{})
+func (q *Query) Scope(scopes ...ScopeFunc) *Query {
+	for _, scope := range scopes {
+		q = scope(q)
+	}
+	return q
+}
+
+var scopeRegistry = map[reflect.Type]map[string]ScopeFunc{}
+var scopeRegistryMutex = sync.RWMutex{}
+
+// RegisterScope registers a named scope for the type of model. Named scopes
+// are applied with `Scopes` once the model a query runs against is known.
+//
+//	goala.RegisterScope(&User{}, "active", Active)
+func RegisterScope(model interface{}, name string, scope ScopeFunc) {
+	t := modelType(model)
+	scopeRegistryMutex.Lock()
+	defer scopeRegistryMutex.Unlock()
+	if _, ok := scopeRegistry[t]; !ok {
+		scopeRegistry[t] = map[string]ScopeFunc{}
+	}
+	scopeRegistry[t][name] = scope
+}
+
+func lookupScope(t reflect.Type, name string) (ScopeFunc, bool) {
+	scopeRegistryMutex.RLock()
+	defer scopeRegistryMutex.RUnlock()
+	scope, ok := scopeRegistry[t][name]
+	return scope, ok
+}
+
+// Scopes applies the named scopes registered for the model of the query.
+//
+//	c.Scopes("active").All(&[]User{})
+func (c *Connection) Scopes(names ...string) *Query {
+	return Q(c).Scopes(names...)
+}
+
+// Scopes applies the named scopes registered for the model of the query.
+// The scopes are looked up when the query is executed.
+//
+//	q.Scopes("active").All(&[]User{})
+func (q *Query) Scopes(names ...string) *Query {
+	q.namedScopes = append(q.namedScopes, names...)
+	return q
+}
+
+// resolveScopes returns a copy of the query with the named scopes of the
+// model applied or the first error found while building the query
+func (q Query) resolveScopes(model *Model) (Query, error) {
+	if q.err != nil {
+		return q, q.err
+	}
+	if len(q.namedScopes) == 0 || model == nil {
+		return q, nil
+	}
+	names := q.namedScopes
+	q.namedScopes = nil
+	q.whereClauses = append(clauses{}, q.whereClauses...)
+	q.orderClauses = append(clauses{}, q.orderClauses...)
+	q.fromClauses = append(fromClauses{}, q.fromClauses...)
+	q.addColumns = append([]string{}, q.addColumns...)
+	q.excludeColumns = append([]string{}, q.excludeColumns...)
+	t := modelType(model.Value)
+	scoped := &q
+	for _, name := range names {
+		scope, ok := lookupScope(t, name)
+		if !ok {
+			return q, errors.Errorf("%s: missing scope: %s", t.Name(), name)
+		}
+		scoped = scope(scoped)
+	}
+	return *scoped, nil
+}
+
+// modelType returns the struct type of a model, a pointer to one or a slice
+// of either
+func modelType(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return t
+}
@@ -3,8 +3,8 @@ package goala
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
-	"sync"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -65,7 +65,7 @@ func (sq *sqlBuilder) compile() {
 func (sq *sqlBuilder) buildSelectSQL() string {
 	cols := sq.buildColumns()
 
-	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ","), sq.Model.TableName())
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ","), sq.buildFrom())
 	sql = sq.buildWhereClauses(sql)
 	sql = sq.buildOrderClauses(sql)
 	sql = sq.buildPaginationClauses(sql)
@@ -73,8 +73,72 @@ func (sq *sqlBuilder) buildSelectSQL() string {
 	return sql
 }
 
+// buildUpdateSQL returns a statement that sets the values on every row
+// matched by the where clauses of the query
+func (sq *sqlBuilder) buildUpdateSQL(values map[string]interface{}) (string, []interface{}, error) {
+	cols := make([]string, 0, len(values))
+	for col := range values {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	sets := make([]string, len(cols))
+	for i, col := range cols {
+		sets[i] = fmt.Sprintf("%s = ?", col)
+		sq.args = append(sq.args, values[col])
+	}
+	sql := fmt.Sprintf("UPDATE %s SET %s", sq.Model.TableName(), strings.Join(sets, ", "))
+	sql = sq.buildWhereClauses(sql)
+	return sq.bindStatement(sql)
+}
+
+// buildDeleteSQL returns a statement that deletes every row matched by the
+// where clauses of the query
+func (sq *sqlBuilder) buildDeleteSQL() (string, []interface{}, error) {
+	sql := fmt.Sprintf("DELETE FROM %s", sq.Model.TableName())
+	sql = sq.buildWhereClauses(sql)
+	return sq.bindStatement(sql)
+}
+
+// bindStatement expands slice arguments of `IN (?)` clauses and translates
+// the statement for the dialect
+func (sq *sqlBuilder) bindStatement(sql string) (string, []interface{}, error) {
+	args := sq.args
+	if inRegex.MatchString(sql) {
+		var err error
+		sql, args, err = sqlx.In(sql, args...)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return sq.Query.Connection.Dialect.TranslateSQL(sql), args, nil
+}
+
+// buildFrom returns the from clauses of the query or, when there are none,
+// the table of the model
+func (sq *sqlBuilder) buildFrom() string {
+	fc := sq.Query.fromClauses
+	if len(fc) == 0 {
+		return sq.Model.TableName()
+	}
+	sq.args = append(sq.args, fc.Args()...)
+	return fc.String()
+}
+
+// buildWhereClauses joins the where clauses of the query. The clauses are
+// wrapped in parentheses when soft deleted records are filtered out so that
+// an OR in them cannot match those records.
 func (sq *sqlBuilder) buildWhereClauses(sql string) string {
 	wc := sq.Query.whereClauses
+	if !sq.Query.unscoped && sq.Model != nil {
+		if col, ok := sq.Model.deletedAtColumn(); ok {
+			notDeleted := clause{col + " IS NULL", nil}
+			if len(wc) > 0 {
+				wc = clauses{{fmt.Sprintf("(%s)", wc.Join(" AND ")), wc.Args()}, notDeleted}
+			} else {
+				wc = clauses{notDeleted}
+			}
+		}
+	}
 	if len(wc) > 0 {
 		sql = fmt.Sprintf("%s WHERE %s", sql, wc.Join(" AND "))
 		for _, arg := range wc.Args() {
@@ -102,25 +166,14 @@ func (sq *sqlBuilder) buildPaginationClauses(sql string) string {
 	return sql
 }
 
-var columnCache = map[string][]string{}
-var columnCacheMutex = sync.Mutex{}
-
-// buildColumns either caches or creates new columns for a table
+// buildColumns returns the columns of the select. The columns of a model
+// come from its cached metadata.
 func (sq *sqlBuilder) buildColumns() []string {
-	tableName := sq.Model.TableName()
-
-	columnCacheMutex.Lock()
-	cols, ok := columnCache[tableName]
-	columnCacheMutex.Unlock()
-
-	if ok {
-		return cols
+	if sq.Model == nil {
+		if len(sq.Query.addColumns) == 0 {
+			return []string{"*"}
+		}
+		return sq.Query.addColumns
 	}
-
-	cols = sq.Model.ColumnSlice()
-	columnCacheMutex.Lock()
-	columnCache[tableName] = cols
-	columnCacheMutex.Unlock()
-
-	return cols
+	return sq.Model.metadata().projection(sq.Query.addColumns, sq.Query.excludeColumns)
 }
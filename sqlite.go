@@ -1,6 +1,11 @@
 package goala
 
 import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/estenssoros/goala/nulls"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/pkg/errors"
@@ -28,7 +33,16 @@ func (s *sqlite3) Name() string {
 }
 
 func (s *sqlite3) TranslateSQL(sql string) string {
-	return sql
+	return genericTranslateSQL(sql, s.Name())
+}
+
+func (s *sqlite3) Quote(name string) string {
+	return quoteIdentifier(name, `"`)
+}
+
+// JSONExtract reads the value at a JSON path bound as the next argument
+func (s *sqlite3) JSONExtract(column string) string {
+	return fmt.Sprintf("json_extract(%s, ?)", column)
 }
 
 func (s *sqlite3) Create(db *sqlx.DB, model *Model) error {
@@ -36,13 +50,22 @@ func (s *sqlite3) Create(db *sqlx.DB, model *Model) error {
 }
 
 func (s *sqlite3) CreateMany(db *sqlx.DB, model *Model) error {
-	return errors.Wrap(genericCreateMany(db, model), "sqlite3 create")
+	return errors.Wrap(genericCreateMany(db, model, s.Name()), "sqlite3 create")
 }
 
 func (s *sqlite3) Update(db *sqlx.DB, model *Model) error {
 	return errors.Wrap(genericUpdate(db, model), "sqlite3 update")
 }
 
+func (s *sqlite3) UpdateColumns(db *sqlx.DB, model *Model, cols []string) error {
+	return errors.Wrap(genericUpdateColumns(db, model, cols), "sqlite3 update columns")
+}
+
+func (s *sqlite3) UpdateAll(db *sqlx.DB, model *Model, query Query, values map[string]interface{}) (int64, error) {
+	n, err := genericUpdateAll(db, model, query, values)
+	return n, errors.Wrap(err, "sqlite3 update all")
+}
+
 func (s *sqlite3) Destroy(db *sqlx.DB, model *Model) error {
 	return errors.Wrap(genericDestroy(db, model), "sqlite3 destroy")
 }
@@ -51,12 +74,103 @@ func (s *sqlite3) DestroyMany(db *sqlx.DB, model *Model) error {
 	return errors.Wrap(genericDestroyMany(db, model), "sqlite3 destroy many")
 }
 
+func (s *sqlite3) DeleteWhere(db *sqlx.DB, model *Model, query Query) (int64, error) {
+	n, err := genericDeleteWhere(db, model, query)
+	return n, errors.Wrap(err, "sqlite3 delete where")
+}
+
+func (s *sqlite3) SoftDestroy(db *sqlx.DB, model *Model, now time.Time) error {
+	return errors.Wrap(genericSoftDestroy(db, model, now), "sqlite3 soft destroy")
+}
+
+func (s *sqlite3) SoftDestroyMany(db *sqlx.DB, model *Model, now time.Time) error {
+	return errors.Wrap(genericSoftDestroyMany(db, model, now), "sqlite3 soft destroy many")
+}
+
 func (s *sqlite3) SelectOne(db *sqlx.DB, model *Model, query Query) error {
-	return errors.Wrap(genericSelectOne(db, model, query), "sqlite3 select one")
+	if !hasTimeFields(model) {
+		return errors.Wrap(genericSelectOne(db, model, query), "sqlite3 select one")
+	}
+	return errors.Wrap(selectOneWith(db, model, query, sqliteScanRow), "sqlite3 select one")
 }
 
 func (s *sqlite3) SelectMany(db *sqlx.DB, models *Model, query Query) error {
-	return errors.Wrap(genericSelectMany(db, models, query), "sqlite3 select many")
+	if !hasTimeFields(models) {
+		return errors.Wrap(genericSelectMany(db, models, query), "sqlite3 select many")
+	}
+	return errors.Wrap(selectManyWith(db, models, query, sqliteScanRow), "sqlite3 select many")
+}
+
+func (s *sqlite3) SelectRows(db *sqlx.DB, model *Model, query Query) (*sqlx.Rows, error) {
+	rows, err := genericSelectRows(db, model, query)
+	return rows, errors.Wrap(err, "sqlite3 select rows")
+}
+
+func (s *sqlite3) ScanRow(rows *sqlx.Rows, model *Model) error {
+	return errors.Wrap(sqliteScanRow(rows, model), "sqlite3 scan row")
+}
+
+// hasTimeFields reports whether the model has `time.Time` fields, which
+// SQLite only returns as times for columns declared as a date or time
+func hasTimeFields(model *Model) bool {
+	for _, col := range model.metadata().Fields {
+		if col.Type() == timeType {
+			return true
+		}
+	}
+	return false
+}
+
+// sqliteScanRow scans the current row into the model. `time.Time` fields
+// are read with nulls.ParseTime so that the text and epoch timestamps of
+// columns that are not declared as DATETIME can be scanned as well.
+func sqliteScanRow(rows *sqlx.Rows, model *Model) error {
+	if !hasTimeFields(model) {
+		return genericScanRow(rows, model)
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	meta := model.metadata()
+	v := reflect.Indirect(reflect.ValueOf(model.Value))
+	dest := make([]interface{}, len(cols))
+	for i, name := range cols {
+		col, ok := meta.column(name)
+		if !ok {
+			return errors.Errorf("missing destination name %s in %T", name, model.Value)
+		}
+		fv := fieldValueAlloc(v, col)
+		if col.Type() == timeType {
+			dest[i] = timeScanner{fv}
+		} else {
+			dest[i] = fv.Addr().Interface()
+		}
+	}
+	return rows.Scan(dest...)
+}
+
+// timeScanner scans a driver value into a `time.Time` or `*time.Time`
+// field. NULL leaves the zero value.
+type timeScanner struct {
+	field reflect.Value
+}
+
+func (s timeScanner) Scan(value interface{}) error {
+	s.field.Set(reflect.Zero(s.field.Type()))
+	if value == nil {
+		return nil
+	}
+	t, err := nulls.ParseTime(value)
+	if err != nil {
+		return err
+	}
+	if s.field.Kind() == reflect.Ptr {
+		s.field.Set(reflect.ValueOf(&t))
+		return nil
+	}
+	s.field.Set(reflect.ValueOf(t))
+	return nil
 }
 
 func (s *sqlite3) SQLView(db *sqlx.DB, models *Model, format map[string]string) error {
@@ -0,0 +1,120 @@
+package goala
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// From sets the source of the query. It accepts a table name, a model whose
+// table should be used or another `*Query`, which is compiled as a
+// subquery. Name a subquery with `As`.
+//
+//	c.From("users")
+//	c.From(&User{})
+//	c.From(c.Select("id").From(&User{}).As("u"))
+func (c *Connection) From(from interface{}) *Query {
+	return Q(c).From(from)
+}
+
+// From sets the source of the query. It accepts a table name, a model whose
+// table should be used or another `*Query`, which is compiled as a
+// subquery. Name a subquery with `As`.
+//
+//	q.From("users")
+//	q.From(&User{})
+//	q.From(c.Select("id").From(&User{}).As("u"))
+func (q *Query) From(from interface{}) *Query {
+	switch f := from.(type) {
+	case string:
+		q.fromClauses = append(q.fromClauses, fromClause{From: f})
+	case *Query:
+		sql, args, err := f.subquery()
+		if err != nil {
+			q.addError(errors.Wrap(err, "from"))
+			return q
+		}
+		sql = fmt.Sprintf("(%s)", sql)
+		if f.alias != "" {
+			sql = fmt.Sprintf("%s AS %s", sql, f.alias)
+		}
+		q.fromClauses = append(q.fromClauses, fromClause{From: sql, Arguments: args})
+	default:
+		m := q.Connection.newModel(from)
+		if q.model == nil {
+			q.model = m
+		}
+		q.fromClauses = append(q.fromClauses, fromClause{From: m.TableName()})
+	}
+	return q
+}
+
+// As names the query when it is used as a subquery in `From`.
+func (q *Query) As(alias string) *Query {
+	q.alias = alias
+	return q
+}
+
+// subquery compiles the query on its own so that it can be embedded in
+// another one
+func (q *Query) subquery() (string, []interface{}, error) {
+	if q.model == nil && len(q.fromClauses) == 0 && q.RawSQL.Fragment == "" {
+		return "", nil, errors.New("subquery requires From")
+	}
+	return q.ToSQLErr(nil)
+}
+
+// expandSubqueries replaces every placeholder whose argument is a `*Query`
+// with the SQL of that query and splices its arguments in at the same
+// position. It reports whether any subquery was found.
+func expandSubqueries(stmt string, args []interface{}) (string, []interface{}, bool, error) {
+	found := false
+	for _, arg := range args {
+		if _, ok := arg.(*Query); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return stmt, args, false, nil
+	}
+	var sb strings.Builder
+	out := []interface{}{}
+	n := 0
+	for i := 0; i < len(stmt); i++ {
+		if stmt[i] != '?' || n >= len(args) {
+			sb.WriteByte(stmt[i])
+			continue
+		}
+		sub, ok := args[n].(*Query)
+		if !ok {
+			sb.WriteByte('?')
+			out = append(out, args[n])
+			n++
+			continue
+		}
+		sql, subArgs, err := sub.subquery()
+		if err != nil {
+			return "", nil, true, err
+		}
+		if !enclosed(stmt, i) {
+			sql = fmt.Sprintf("(%s)", sql)
+		}
+		sb.WriteString(sql)
+		out = append(out, subArgs...)
+		n++
+	}
+	if n < len(args) {
+		return "", nil, true, errors.Errorf("%d arguments for %d placeholders", len(args), n)
+	}
+	return sb.String(), out, true, nil
+}
+
+// enclosed reports whether the character at i is already wrapped in
+// parentheses, as in `IN (?)`
+func enclosed(stmt string, i int) bool {
+	before := strings.TrimRight(stmt[:i], " \t\n")
+	after := strings.TrimLeft(stmt[i+1:], " \t\n")
+	return strings.HasSuffix(before, "(") && strings.HasPrefix(after, ")")
+}
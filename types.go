@@ -0,0 +1,72 @@
+package goala
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"sync"
+)
+
+// ColumnType describes how values of a Go type are stored. DataType is one
+// of the data type constants and SQL, when set, replaces the column type
+// used in generated tables.
+//
+//	goala.RegisterType(reflect.TypeOf(decimal.Decimal{}), goala.ColumnType{DataType: goala.ValuerType, SQL: "DECIMAL(18,4)"})
+type ColumnType struct {
+	DataType int
+	SQL      string
+}
+
+// ColumnTyper is implemented by types that describe their own column type
+type ColumnTyper interface {
+	ColumnType() ColumnType
+}
+
+var columnTypes = map[reflect.Type]ColumnType{}
+var columnTypesMutex = sync.RWMutex{}
+
+// RegisterType sets the column type of a Go type. Registered types win over
+// ColumnTyper and the built in mapping.
+func RegisterType(t reflect.Type, ct ColumnType) {
+	columnTypesMutex.Lock()
+	columnTypes[t] = ct
+	columnTypesMutex.Unlock()
+
+	// metadata computed before the registration is stale
+	metadataCacheMutex.Lock()
+	metadataCache = map[metadataKey]*structMetadata{}
+	metadataCacheMutex.Unlock()
+}
+
+var columnTyperType = reflect.TypeOf((*ColumnTyper)(nil)).Elem()
+
+// lookupColumnType returns the column type of a registered type or of a
+// type implementing ColumnTyper
+func lookupColumnType(t reflect.Type) (ColumnType, bool) {
+	columnTypesMutex.RLock()
+	ct, ok := columnTypes[t]
+	columnTypesMutex.RUnlock()
+	if ok {
+		return ct, true
+	}
+	if t.Implements(columnTyperType) || reflect.PtrTo(t).Implements(columnTyperType) {
+		return reflect.New(t).Interface().(ColumnTyper).ColumnType(), true
+	}
+	return ColumnType{}, false
+}
+
+// isValuer reports whether a type is converted by database/sql through
+// driver.Valuer and sql.Scanner
+func isValuer(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return t.Implements(valuerType) || pt.Implements(valuerType) || pt.Implements(scannerType)
+}
+
+// driverValue converts a field to a value the driver understands, calling
+// Value on types that implement driver.Valuer
+func driverValue(value reflect.Value) (driver.Value, error) {
+	v := value.Interface()
+	if value.CanAddr() && !value.Type().Implements(valuerType) && value.Addr().Type().Implements(valuerType) {
+		v = value.Addr().Interface()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
@@ -0,0 +1,264 @@
+package goala
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// ValidationErrors maps the column of a field to the messages of the
+// validations it failed.
+type ValidationErrors map[string][]string
+
+// Add appends a message for a field.
+func (v ValidationErrors) Add(field, msg string) {
+	v[field] = append(v[field], msg)
+}
+
+// HasAny reports whether any validation failed.
+func (v ValidationErrors) HasAny() bool {
+	return len(v) > 0
+}
+
+func (v ValidationErrors) Error() string {
+	fields := make([]string, 0, len(v))
+	for field := range v {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	out := make([]string, len(fields))
+	for i, field := range fields {
+		out[i] = fmt.Sprintf("%s: %s", field, strings.Join(v[field], ", "))
+	}
+	return strings.Join(out, "; ")
+}
+
+// Validateable lets a model add its own validations to the ones declared
+// with `validate` struct tags. The error is reserved for failures to run the
+// validations, such as a database error.
+type Validateable interface {
+	Validate(*Connection) (ValidationErrors, error)
+}
+
+// ValidateAndCreate validates the model, or each model of a slice, and only
+// creates it when there are no validation errors.
+func (c *Connection) ValidateAndCreate(model interface{}) (ValidationErrors, error) {
+	verrs, err := c.Validate(model)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
+	return verrs, c.Create(model)
+}
+
+// ValidateAndUpdate validates the model, or each model of a slice, and only
+// updates it when there are no validation errors.
+func (c *Connection) ValidateAndUpdate(model interface{}) (ValidationErrors, error) {
+	verrs, err := c.Validate(model)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
+	return verrs, c.Update(model)
+}
+
+// Validate runs the validations declared with `validate` struct tags and
+// those of `Validateable` against the model or each model of a slice. Errors
+// of a slice element are keyed by its index, e.g. "2.email".
+//
+// The tag holds comma separated rules. `regex` consumes the rest of the tag
+// so that the expression may contain commas.
+//
+//	Name  string `db:"name" validate:"required,len=1:50"`
+//	Age   int    `db:"age" validate:"range=0:130"`
+//	Email string `db:"email" validate:"unique,regex=^[^@]+@[^@]+$"`
+func (c *Connection) Validate(model interface{}) (ValidationErrors, error) {
+	sm := c.newModel(model)
+	verrs := ValidationErrors{}
+	i := 0
+	err := sm.iterate(func(m *Model) error {
+		errs, err := c.validateModel(m)
+		if err != nil {
+			return err
+		}
+		for field, msgs := range errs {
+			if sm.isSlice() {
+				field = fmt.Sprintf("%d.%s", i, field)
+			}
+			verrs[field] = append(verrs[field], msgs...)
+		}
+		i++
+		return nil
+	})
+	return verrs, err
+}
+
+// validateModel validates the columns of a model, keyed by column name
+func (c *Connection) validateModel(m *Model) (ValidationErrors, error) {
+	verrs := ValidationErrors{}
+	values := reflect.ValueOf(m.Value).Elem()
+	for _, col := range m.metadata().Fields {
+		tag := col.Field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		value, ok := fieldValue(values, col)
+		if !ok {
+			value = reflect.Zero(col.Field.Type)
+		}
+		if err := c.validateField(m, col.Name, value, tag, verrs); err != nil {
+			return nil, errors.Wrapf(err, "validate %s", col.Name)
+		}
+	}
+	if v, ok := m.Value.(Validateable); ok {
+		errs, err := v.Validate(c)
+		if err != nil {
+			return nil, err
+		}
+		for field, msgs := range errs {
+			verrs[field] = append(verrs[field], msgs...)
+		}
+	}
+	return verrs, nil
+}
+
+func (c *Connection) validateField(m *Model, name string, value reflect.Value, tag string, verrs ValidationErrors) error {
+	var val interface{}
+	if value.Kind() != reflect.Ptr {
+		val = value.Interface()
+	} else if !value.IsNil() {
+		val = value.Elem().Interface()
+	}
+	n, isNullable := val.(nullable)
+	if isNullable {
+		val = n.Interface()
+	}
+	rules := tag
+	for rules != "" {
+		var rule string
+		if strings.HasPrefix(rules, "regex=") {
+			rule, rules = rules, ""
+		} else if i := strings.Index(rules, ","); i >= 0 {
+			rule, rules = rules[:i], rules[i+1:]
+		} else {
+			rule, rules = rules, ""
+		}
+		key, arg := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			key, arg = rule[:i], rule[i+1:]
+		}
+		switch key {
+		case "required":
+			if val == nil || (!isNullable && reflect.ValueOf(val).IsZero()) {
+				verrs.Add(name, "is required")
+			}
+		case "len":
+			s, ok := val.(string)
+			if !ok {
+				continue
+			}
+			min, max, err := parseBounds(arg)
+			if err != nil {
+				return err
+			}
+			if n := float64(utf8.RuneCountInString(s)); (min != nil && n < *min) || (max != nil && n > *max) {
+				verrs.Add(name, fmt.Sprintf("length must be within %s", arg))
+			}
+		case "range":
+			n, ok := toFloat(val)
+			if !ok {
+				continue
+			}
+			min, max, err := parseBounds(arg)
+			if err != nil {
+				return err
+			}
+			if (min != nil && n < *min) || (max != nil && n > *max) {
+				verrs.Add(name, fmt.Sprintf("must be within %s", arg))
+			}
+		case "regex":
+			s, ok := val.(string)
+			if !ok {
+				continue
+			}
+			re, err := regexp.Compile(arg)
+			if err != nil {
+				return err
+			}
+			if !re.MatchString(s) {
+				verrs.Add(name, fmt.Sprintf("must match %s", arg))
+			}
+		case "unique":
+			if val == nil {
+				continue
+			}
+			taken, err := c.isTaken(m, name, val)
+			if err != nil {
+				return err
+			}
+			if taken {
+				verrs.Add(name, "is already taken")
+			}
+		default:
+			return errors.Errorf("unknown validation: %s", key)
+		}
+	}
+	return nil
+}
+
+// isTaken reports whether another record of the model's table has the value
+// in the column. Soft deleted records do not count.
+func (c *Connection) isTaken(m *Model, column string, value interface{}) (bool, error) {
+	var count int
+	stmt := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?", m.TableName(), column)
+	args := []interface{}{value}
+	if pk, id, err := m.primaryKey(); err == nil {
+		stmt += fmt.Sprintf(" AND %s <> ?", pk)
+		args = append(args, id)
+	}
+	if col, ok := m.deletedAtColumn(); ok {
+		stmt += fmt.Sprintf(" AND %s IS NULL", col)
+	}
+	if err := c.db().Get(&count, stmt, args...); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// parseBounds parses a `min:max` argument where either bound may be left
+// out
+func parseBounds(arg string) (*float64, *float64, error) {
+	parts := strings.Split(arg, ":")
+	if len(parts) != 2 {
+		return nil, nil, errors.Errorf("bounds must be min:max: %s", arg)
+	}
+	bounds := make([]*float64, 2)
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "parsing bounds %s", arg)
+		}
+		bounds[i] = &f
+	}
+	return bounds[0], bounds[1], nil
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,152 @@
+package goala
+
+import (
+	"testing"
+
+	"github.com/estenssoros/goala/nulls"
+	"github.com/satori/go.uuid"
+)
+
+type validatedStruct struct {
+	ID    uuid.UUID    `db:"id"`
+	Name  string       `db:"name" validate:"required,len=2:5"`
+	Age   int          `db:"age" validate:"range=0:130"`
+	Email string       `db:"email" validate:"unique,regex=^[^@,]+@[^@,]+$"`
+	Note  nulls.String `db:"note" validate:"required"`
+}
+
+func (v validatedStruct) TableName() string {
+	return "validated"
+}
+
+func (v *validatedStruct) Validate(c *Connection) (ValidationErrors, error) {
+	verrs := ValidationErrors{}
+	if v.Name == "admin" {
+		verrs.Add("name", "is reserved")
+	}
+	return verrs, nil
+}
+
+func TestValidateAndCreate(t *testing.T) {
+	db := newTestConnection(t, &validatedStruct{})
+	defer db.Close()
+	valid := &validatedStruct{Name: "mark", Age: 30, Email: "mark@example.com", Note: nulls.NewString("")}
+	verrs, err := db.ValidateAndCreate(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verrs.HasAny() {
+		t.Fatal(verrs)
+	}
+	invalid := &validatedStruct{Name: "admin", Age: 200, Email: "mark@example.com"}
+	verrs, err = db.ValidateAndCreate(invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"name": 1, "age": 1, "email": 1, "note": 1}
+	for field, n := range want {
+		if have := len(verrs[field]); n != have {
+			t.Errorf("%s: want: %d have: %d (%v)", field, n, have, verrs)
+		}
+	}
+	valid.Name = "x"
+	verrs, err = db.ValidateAndUpdate(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(verrs["name"]); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+}
+
+type uniqueSoftStruct struct {
+	ID        uuid.UUID  `db:"id"`
+	Email     string     `db:"email" validate:"unique"`
+	DeletedAt nulls.Time `db:"deleted_at"`
+}
+
+func (uniqueSoftStruct) TableName() string {
+	return "unique_soft"
+}
+
+func TestValidateUniqueIgnoresSoftDeleted(t *testing.T) {
+	db := newTestConnection(t, &uniqueSoftStruct{})
+	defer db.Close()
+	record := &uniqueSoftStruct{Email: "mark@example.com"}
+	if err := db.Create(record); err != nil {
+		t.Fatal(err)
+	}
+	verrs, err := db.Validate(&uniqueSoftStruct{Email: "mark@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(verrs["email"]); want != have {
+		t.Errorf("want: %d have: %d", want, have)
+	}
+	if err := db.Destroy(record); err != nil {
+		t.Fatal(err)
+	}
+	verrs, err = db.Validate(&uniqueSoftStruct{Email: "mark@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verrs.HasAny() {
+		t.Errorf("want no errors, have: %v", verrs)
+	}
+}
+
+type namedValidatedStruct struct {
+	ID        uuid.UUID
+	FirstName string `validate:"unique"`
+}
+
+func TestValidateNamingStrategy(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	db.SetNamingStrategy(SnakeCase{})
+	if err := db.CreateTable(&namedValidatedStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&namedValidatedStruct{FirstName: "mark"}); err != nil {
+		t.Fatal(err)
+	}
+	verrs, err := db.Validate(&namedValidatedStruct{FirstName: "mark"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(verrs["first_name"]); want != have {
+		t.Errorf("want: %d have: %d (%v)", want, have, verrs)
+	}
+}
+
+type validatedContact struct {
+	Email string `db:"email" validate:"required"`
+}
+
+type embeddedValidatedStruct struct {
+	ID       uuid.UUID `db:"id"`
+	Nickname *string   `db:"nickname" validate:"required,len=2:5"`
+	*validatedContact
+}
+
+func TestValidateEmbeddedAndPointerFields(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	verrs, err := db.Validate(&embeddedValidatedStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"nickname", "email"} {
+		if want, have := 1, len(verrs[field]); want != have {
+			t.Errorf("%s: want: %d have: %d (%v)", field, want, have, verrs)
+		}
+	}
+	nickname := "toolong"
+	verrs, err = db.Validate(&embeddedValidatedStruct{Nickname: &nickname, validatedContact: &validatedContact{Email: "m@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(verrs["nickname"]); want != have || len(verrs) != 1 {
+		t.Errorf("want only a nickname error, have: %v", verrs)
+	}
+}
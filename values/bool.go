@@ -1 +1,34 @@
 package values
+
+import (
+	"database/sql/driver"
+	"strconv"
+)
+
+type boolValue bool
+
+// Bool returns a boolean value
+func Bool(b bool) Value {
+	return boolValue(b)
+}
+
+func (b boolValue) Value() (driver.Value, error) {
+	return bool(b), nil
+}
+
+// Literal renders TRUE and FALSE on postgres and 1 and 0 elsewhere
+func (b boolValue) Literal(dialect string) string {
+	switch {
+	case dialect == Postgres && bool(b):
+		return "TRUE"
+	case dialect == Postgres:
+		return "FALSE"
+	case bool(b):
+		return "1"
+	}
+	return "0"
+}
+
+func (b boolValue) String() string {
+	return strconv.FormatBool(bool(b))
+}
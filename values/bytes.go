@@ -0,0 +1,32 @@
+package values
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type bytesValue []byte
+
+// Bytes returns a binary value
+func Bytes(b []byte) Value {
+	if b == nil {
+		return Null()
+	}
+	return bytesValue(b)
+}
+
+func (b bytesValue) Value() (driver.Value, error) {
+	return []byte(b), nil
+}
+
+// Literal renders the bytes as a hex blob literal
+func (b bytesValue) Literal(dialect string) string {
+	if dialect == Postgres {
+		return fmt.Sprintf(`'\x%x'::bytea`, []byte(b))
+	}
+	return fmt.Sprintf("X'%x'", []byte(b))
+}
+
+func (b bytesValue) String() string {
+	return string(b)
+}
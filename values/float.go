@@ -1 +1,30 @@
 package values
+
+import (
+	"database/sql/driver"
+	"math"
+	"strconv"
+)
+
+type floatValue float64
+
+// Float returns a floating point value. NaN and infinities have no SQL
+// literal and render as NULL.
+func Float(f float64) Value {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Null()
+	}
+	return floatValue(f)
+}
+
+func (f floatValue) Value() (driver.Value, error) {
+	return float64(f), nil
+}
+
+func (f floatValue) Literal(dialect string) string {
+	return f.String()
+}
+
+func (f floatValue) String() string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 64)
+}
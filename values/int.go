@@ -1 +1,25 @@
 package values
+
+import (
+	"database/sql/driver"
+	"strconv"
+)
+
+type intValue int64
+
+// Int returns an integer value
+func Int(i int64) Value {
+	return intValue(i)
+}
+
+func (i intValue) Value() (driver.Value, error) {
+	return int64(i), nil
+}
+
+func (i intValue) Literal(dialect string) string {
+	return i.String()
+}
+
+func (i intValue) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
@@ -1 +1,58 @@
 package values
+
+import (
+	"database/sql/driver"
+	"strings"
+)
+
+type stringValue string
+
+// String returns a string value
+func String(s string) Value {
+	return stringValue(s)
+}
+
+func (s stringValue) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Literal quotes the string. MySQL escapes with backslashes, the other
+// dialects double single quotes.
+func (s stringValue) Literal(dialect string) string {
+	if dialect == MySQL {
+		return "'" + escapeMySQL(string(s)) + "'"
+	}
+	return "'" + strings.Replace(string(s), "'", "''", -1) + "'"
+}
+
+func (s stringValue) String() string {
+	return string(s)
+}
+
+// escapeMySQL escapes the characters MySQL treats specially in a quoted
+// string
+func escapeMySQL(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			b.WriteString(`\0`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\032':
+			b.WriteString(`\Z`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -1 +1,35 @@
 package values
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Layouts of time literals. SQLite keeps the zone in a format its driver
+// parses back, MySQL DATETIME has no zone so times are written in UTC.
+const (
+	SQLiteTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+	MySQLTimeFormat  = "2006-01-02 15:04:05.999999"
+)
+
+type timeValue time.Time
+
+// Time returns a time value
+func Time(t time.Time) Value {
+	return timeValue(t)
+}
+
+func (t timeValue) Value() (driver.Value, error) {
+	return time.Time(t), nil
+}
+
+func (t timeValue) Literal(dialect string) string {
+	if dialect == MySQL {
+		return "'" + time.Time(t).UTC().Format(MySQLTimeFormat) + "'"
+	}
+	return "'" + t.String() + "'"
+}
+
+func (t timeValue) String() string {
+	return time.Time(t).Format(SQLiteTimeFormat)
+}
@@ -0,0 +1,80 @@
+// Package values provides typed SQL values. A value binds as a query
+// argument through driver.Valuer or renders as an escaped literal for a
+// dialect, e.g. in generated DDL and multi row inserts.
+//
+//	values.String("O'Brien").Literal("sqlite3") // 'O''Brien'
+package values
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Dialects with their own literal rules. Any other dialect name renders
+// like SQLite.
+const (
+	SQLite3  = "sqlite3"
+	MySQL    = "mysql"
+	Postgres = "postgres"
+)
+
+// Value is a typed SQL value
+type Value interface {
+	driver.Valuer
+	// Literal renders the value as an escaped SQL literal for a dialect
+	Literal(dialect string) string
+	// String renders the value unquoted, with NULL for null
+	String() string
+}
+
+type nullValue struct{}
+
+// Null returns the SQL NULL value
+func Null() Value {
+	return nullValue{}
+}
+
+func (nullValue) Value() (driver.Value, error) {
+	return nil, nil
+}
+
+func (nullValue) Literal(dialect string) string {
+	return "NULL"
+}
+
+func (nullValue) String() string {
+	return "NULL"
+}
+
+// Of returns the value of a driver value: nil, int64, float64, bool,
+// []byte, string or time.Time.
+func Of(v driver.Value) (Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return Null(), nil
+	case int64:
+		return Int(v), nil
+	case float64:
+		return Float(v), nil
+	case bool:
+		return Bool(v), nil
+	case []byte:
+		return Bytes(v), nil
+	case string:
+		return String(v), nil
+	case time.Time:
+		return Time(v), nil
+	}
+	return nil, fmt.Errorf("values: unsupported driver value %T", v)
+}
+
+// Placeholder returns the bind parameter for the nth argument, counting
+// from 1.
+func Placeholder(dialect string, n int) string {
+	if dialect == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
@@ -0,0 +1,178 @@
+package goala
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/estenssoros/goala/nulls"
+	"github.com/estenssoros/goala/values"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestValueLiterals(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 600, time.FixedZone("", 3600))
+	for _, tc := range []struct {
+		value   values.Value
+		dialect string
+		want    string
+	}{
+		{values.String(`O'Brien "x"`), values.SQLite3, `'O''Brien "x"'`},
+		{values.String(`O'Brien "x"`), values.MySQL, `'O\'Brien \"x\"'`},
+		{values.String(`O'Brien`), values.Postgres, `'O''Brien'`},
+		{values.Int(-7), values.SQLite3, "-7"},
+		{values.Float(1.25), values.SQLite3, "1.25"},
+		{values.Float(0), values.MySQL, "0"},
+		{values.Bool(true), values.SQLite3, "1"},
+		{values.Bool(false), values.Postgres, "FALSE"},
+		{values.Time(ts), values.SQLite3, "'2020-01-02 03:04:05.0000006+01:00'"},
+		{values.Time(ts), values.MySQL, "'2020-01-02 02:04:05'"},
+		{values.Bytes([]byte("ab")), values.SQLite3, "X'6162'"},
+		{values.Null(), values.Postgres, "NULL"},
+	} {
+		if have := tc.value.Literal(tc.dialect); tc.want != have {
+			t.Errorf("want: %s have: %s", tc.want, have)
+		}
+	}
+	if want, have := "$3", values.Placeholder(values.Postgres, 3); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+}
+
+func TestTranslateSQL(t *testing.T) {
+	stmt := "SELECT id FROM users WHERE name = '?' AND id IN (?,?) AND age > ?"
+	if want, have := "SELECT id FROM users WHERE name = '?' AND id IN ($1,$2) AND age > $3", genericTranslateSQL(stmt, values.Postgres); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if want, have := stmt, (&sqlite3{}).TranslateSQL(stmt); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+}
+
+type literalStruct struct {
+	Name    string       `db:"name"`
+	Score   float64      `db:"score"`
+	Active  nulls.Bool   `db:"active"`
+	Seen    time.Time    `db:"seen"`
+	Comment nulls.String `db:"comment"`
+}
+
+func TestStringSliceAndTuple(t *testing.T) {
+	v := &literalStruct{
+		Name:   "O'Brien",
+		Score:  1.5,
+		Active: nulls.NewBool(true),
+		Seen:   time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600)),
+	}
+	want := []string{"O'Brien", "1.5", "true", "2020-01-02 03:04:05+01:00", "NULL"}
+	if have := StringSlice(v); !reflect.DeepEqual(want, have) {
+		t.Errorf("want: %v have: %v", want, have)
+	}
+	if want, have := `('O\'Brien',1.5,1,'2020-01-02 02:04:05',NULL)`, StringTuple(v); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	v.Seen = v.Seen.UTC()
+	if want, have := "2020-01-02 02:04:05+00:00", StringSlice(v)[3]; want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+}
+
+type failingValuer struct{}
+
+func (failingValuer) Value() (driver.Value, error) {
+	return nil, errors.New("boom")
+}
+
+func TestStringSliceValuerError(t *testing.T) {
+	defer func() {
+		if want, have := "converting field bad: boom", recover(); want != have {
+			t.Errorf("want: %v have: %v", want, have)
+		}
+	}()
+	StringSlice(&struct {
+		Bad failingValuer `db:"bad"`
+	}{})
+}
+
+func TestCreateManyValuerError(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if _, err := db.DB.Exec("CREATE TABLE failing (bad TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	type failing struct {
+		Bad failingValuer `db:"bad"`
+	}
+	err := db.CreateMany(&[]failing{{}})
+	if err == nil || !strings.Contains(err.Error(), "converting field bad: boom") {
+		t.Errorf("want a conversion error, have: %v", err)
+	}
+}
+
+type defaultsStruct struct {
+	ID     uuid.UUID  `db:"id"`
+	Status string     `db:"status" default:"it's new"`
+	Tries  int        `db:"tries" default:"3"`
+	Active bool       `db:"active" default:"true"`
+	Seen   nulls.Time `db:"seen" default:"NULL"`
+}
+
+func (defaultsStruct) TableName() string { return "defaults" }
+
+func TestColumnDefaults(t *testing.T) {
+	schema, err := createSchema(&defaultsStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, err := schema.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "CREATE TABLE defaults (id TEXT,status TEXT DEFAULT 'it''s new',tries INT DEFAULT 3,active INT DEFAULT 1,seen DATETIME DEFAULT NULL)"
+	if want != sql {
+		t.Errorf("want: %s have: %s", want, sql)
+	}
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&defaultsStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.DB.Exec("INSERT INTO defaults (id) VALUES ('x')"); err != nil {
+		t.Fatal(err)
+	}
+	found := &defaultsStruct{}
+	if err := db.DB.Get(found, "SELECT status, tries, active FROM defaults"); err != nil {
+		t.Fatal(err)
+	}
+	if found.Status != "it's new" || found.Tries != 3 || !found.Active {
+		t.Errorf("unexpected record: %+v", found)
+	}
+	if _, err := createSchema(&struct {
+		defaultsStruct
+		Bad int `db:"bad" default:"x"`
+	}{}); err == nil {
+		t.Error("want an error for an invalid default")
+	}
+}
+
+func TestCreateManyEscaping(t *testing.T) {
+	db := newTestConnection(t)
+	defer db.Close()
+	if err := db.CreateTable(&defaultsStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	status := `it's "quoted" \ here`
+	if err := db.CreateMany(&[]defaultsStruct{{Status: status}}); err != nil {
+		t.Fatal(err)
+	}
+	found := &defaultsStruct{}
+	if err := db.First(found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Status != status {
+		t.Errorf("want: %s have: %s", status, found.Status)
+	}
+}
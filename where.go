@@ -0,0 +1,107 @@
+package goala
+
+import (
+	"reflect"
+)
+
+// nullable is implemented by the types of the nulls package. Interface
+// returns nil when the value is not valid.
+type nullable interface {
+	Interface() interface{}
+}
+
+// WhereOption changes how `WhereStruct` builds its conditions.
+type WhereOption func(*whereOptions)
+
+type whereOptions struct {
+	includeZero bool
+	zeroColumns []string
+}
+
+func (o *whereOptions) includes(column string) bool {
+	if !o.includeZero {
+		return false
+	}
+	return len(o.zeroColumns) == 0 || isin(o.zeroColumns, column)
+}
+
+// IncludeZero makes `WhereStruct` match zero values and invalid nulls of the
+// given columns, or of every column when none are given. Invalid nulls are
+// matched with IS NULL.
+func IncludeZero(columns ...string) WhereOption {
+	return func(o *whereOptions) {
+		o.includeZero = true
+		o.zeroColumns = append(o.zeroColumns, columns...)
+	}
+}
+
+// WhereStruct will append an equality where clause for every non-zero `db`
+// field of v. Fields of the nulls package are matched when they are valid.
+//
+//	c.WhereStruct(&User{Email: "mark@example.com"})
+//	c.WhereStruct(&User{Email: "mark@example.com"}, IncludeZero("admin"))
+func (c *Connection) WhereStruct(v interface{}, opts ...WhereOption) *Query {
+	return Q(c).WhereStruct(v, opts...)
+}
+
+// WhereStruct will append an equality where clause for every non-zero `db`
+// field of v. Fields of the nulls package are matched when they are valid.
+//
+//	q.WhereStruct(&User{Email: "mark@example.com"})
+//	q.WhereStruct(&User{Email: "mark@example.com"}, IncludeZero("admin"))
+func (q *Query) WhereStruct(v interface{}, opts ...WhereOption) *Query {
+	o := &whereOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	fields := reflect.TypeOf(v)
+	values := reflect.ValueOf(v)
+	if values.Kind() == reflect.Ptr {
+		values = values.Elem()
+		fields = fields.Elem()
+	}
+	eq := Eq{}
+	for _, col := range columnFields(fields, q.Connection.newModel(v).namingStrategy()) {
+		colName := col.Name
+		value, ok := fieldValue(values, col)
+		if !ok || (value.Kind() == reflect.Ptr && value.IsNil()) {
+			if o.includes(colName) {
+				eq[colName] = nil
+			}
+			continue
+		}
+		value = reflect.Indirect(value)
+		if n, ok := value.Interface().(nullable); ok {
+			if val := n.Interface(); val != nil || o.includes(colName) {
+				eq[colName] = val
+			}
+			continue
+		}
+		if !value.IsZero() || o.includes(colName) {
+			eq[colName] = value.Interface()
+		}
+	}
+	if len(eq) == 0 {
+		return q
+	}
+	return q.WhereCond(eq)
+}
+
+// WhereMap will append an equality where clause for every entry of the map.
+// A nil value matches NULL.
+//
+//	c.WhereMap(map[string]interface{}{"email": "mark@example.com"})
+func (c *Connection) WhereMap(m map[string]interface{}) *Query {
+	return Q(c).WhereMap(m)
+}
+
+// WhereMap will append an equality where clause for every entry of the map.
+// A nil value matches NULL.
+//
+//	q.WhereMap(map[string]interface{}{"email": "mark@example.com"})
+func (q *Query) WhereMap(m map[string]interface{}) *Query {
+	if len(m) == 0 {
+		return q
+	}
+	return q.WhereCond(Eq(m))
+}